@@ -1,61 +1,178 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
-// source: api/proto/example.proto
-//
-// Note: This is a placeholder file, running `make protos` will overwrite it.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.25.3
+// source: example.proto
 
 package gen
 
 import (
-	"context"
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
 
-	"google.golang.org/grpc"
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ExampleService_GetExample_FullMethodName    = "/api.ExampleService/GetExample"
+	ExampleService_ListExamples_FullMethodName  = "/api.ExampleService/ListExamples"
+	ExampleService_CreateExample_FullMethodName = "/api.ExampleService/CreateExample"
+	ExampleService_UpdateExample_FullMethodName = "/api.ExampleService/UpdateExample"
+	ExampleService_DeleteExample_FullMethodName = "/api.ExampleService/DeleteExample"
+	ExampleService_WatchExamples_FullMethodName = "/api.ExampleService/WatchExamples"
 )
 
-// ExampleServiceServer is the gRPC service interface
+// ExampleServiceClient is the client API for ExampleService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ExampleServiceClient interface {
+	GetExample(ctx context.Context, in *GetExampleRequest, opts ...grpc.CallOption) (*ExampleResponse, error)
+	ListExamples(ctx context.Context, in *ListExamplesRequest, opts ...grpc.CallOption) (*ListExamplesResponse, error)
+	CreateExample(ctx context.Context, in *CreateExampleRequest, opts ...grpc.CallOption) (*ExampleResponse, error)
+	UpdateExample(ctx context.Context, in *UpdateExampleRequest, opts ...grpc.CallOption) (*ExampleResponse, error)
+	DeleteExample(ctx context.Context, in *DeleteExampleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// WatchExamples streams example changes made through any transport
+	WatchExamples(ctx context.Context, in *WatchExamplesRequest, opts ...grpc.CallOption) (ExampleService_WatchExamplesClient, error)
+}
+
+type exampleServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExampleServiceClient(cc grpc.ClientConnInterface) ExampleServiceClient {
+	return &exampleServiceClient{cc}
+}
+
+func (c *exampleServiceClient) GetExample(ctx context.Context, in *GetExampleRequest, opts ...grpc.CallOption) (*ExampleResponse, error) {
+	out := new(ExampleResponse)
+	err := c.cc.Invoke(ctx, ExampleService_GetExample_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exampleServiceClient) ListExamples(ctx context.Context, in *ListExamplesRequest, opts ...grpc.CallOption) (*ListExamplesResponse, error) {
+	out := new(ListExamplesResponse)
+	err := c.cc.Invoke(ctx, ExampleService_ListExamples_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exampleServiceClient) CreateExample(ctx context.Context, in *CreateExampleRequest, opts ...grpc.CallOption) (*ExampleResponse, error) {
+	out := new(ExampleResponse)
+	err := c.cc.Invoke(ctx, ExampleService_CreateExample_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exampleServiceClient) UpdateExample(ctx context.Context, in *UpdateExampleRequest, opts ...grpc.CallOption) (*ExampleResponse, error) {
+	out := new(ExampleResponse)
+	err := c.cc.Invoke(ctx, ExampleService_UpdateExample_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exampleServiceClient) DeleteExample(ctx context.Context, in *DeleteExampleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ExampleService_DeleteExample_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exampleServiceClient) WatchExamples(ctx context.Context, in *WatchExamplesRequest, opts ...grpc.CallOption) (ExampleService_WatchExamplesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ExampleService_ServiceDesc.Streams[0], ExampleService_WatchExamples_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &exampleServiceWatchExamplesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ExampleService_WatchExamplesClient interface {
+	Recv() (*ExampleEvent, error)
+	grpc.ClientStream
+}
+
+type exampleServiceWatchExamplesClient struct {
+	grpc.ClientStream
+}
+
+func (x *exampleServiceWatchExamplesClient) Recv() (*ExampleEvent, error) {
+	m := new(ExampleEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ExampleServiceServer is the server API for ExampleService service.
+// All implementations must embed UnimplementedExampleServiceServer
+// for forward compatibility
 type ExampleServiceServer interface {
 	GetExample(context.Context, *GetExampleRequest) (*ExampleResponse, error)
 	ListExamples(context.Context, *ListExamplesRequest) (*ListExamplesResponse, error)
 	CreateExample(context.Context, *CreateExampleRequest) (*ExampleResponse, error)
+	UpdateExample(context.Context, *UpdateExampleRequest) (*ExampleResponse, error)
+	DeleteExample(context.Context, *DeleteExampleRequest) (*emptypb.Empty, error)
+	// WatchExamples streams example changes made through any transport
+	WatchExamples(*WatchExamplesRequest, ExampleService_WatchExamplesServer) error
 	mustEmbedUnimplementedExampleServiceServer()
 }
 
-// UnimplementedExampleServiceServer is the unimplemented service (embed for forward compatibility)
-type UnimplementedExampleServiceServer struct{}
+// UnimplementedExampleServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedExampleServiceServer struct {
+}
 
 func (UnimplementedExampleServiceServer) GetExample(context.Context, *GetExampleRequest) (*ExampleResponse, error) {
-	return nil, nil
+	return nil, status.Errorf(codes.Unimplemented, "method GetExample not implemented")
 }
 func (UnimplementedExampleServiceServer) ListExamples(context.Context, *ListExamplesRequest) (*ListExamplesResponse, error) {
-	return nil, nil
+	return nil, status.Errorf(codes.Unimplemented, "method ListExamples not implemented")
 }
 func (UnimplementedExampleServiceServer) CreateExample(context.Context, *CreateExampleRequest) (*ExampleResponse, error) {
-	return nil, nil
+	return nil, status.Errorf(codes.Unimplemented, "method CreateExample not implemented")
+}
+func (UnimplementedExampleServiceServer) UpdateExample(context.Context, *UpdateExampleRequest) (*ExampleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateExample not implemented")
+}
+func (UnimplementedExampleServiceServer) DeleteExample(context.Context, *DeleteExampleRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteExample not implemented")
+}
+func (UnimplementedExampleServiceServer) WatchExamples(*WatchExamplesRequest, ExampleService_WatchExamplesServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchExamples not implemented")
 }
 func (UnimplementedExampleServiceServer) mustEmbedUnimplementedExampleServiceServer() {}
 
-// RegisterExampleServiceServer registers the gRPC service
-func RegisterExampleServiceServer(s *grpc.Server, srv ExampleServiceServer) {
-	s.RegisterService(&_ExampleService_serviceDesc, srv)
+// UnsafeExampleServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExampleServiceServer will
+// result in compilation errors.
+type UnsafeExampleServiceServer interface {
+	mustEmbedUnimplementedExampleServiceServer()
 }
 
-var _ExampleService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "api.ExampleService",
-	HandlerType: (*ExampleServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "GetExample",
-			Handler:    _ExampleService_GetExample_Handler,
-		},
-		{
-			MethodName: "ListExamples",
-			Handler:    _ExampleService_ListExamples_Handler,
-		},
-		{
-			MethodName: "CreateExample",
-			Handler:    _ExampleService_CreateExample_Handler,
-		},
-	},
-	Streams: []grpc.StreamDesc{},
+func RegisterExampleServiceServer(s grpc.ServiceRegistrar, srv ExampleServiceServer) {
+	s.RegisterService(&ExampleService_ServiceDesc, srv)
 }
 
 func _ExampleService_GetExample_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
@@ -66,7 +183,10 @@ func _ExampleService_GetExample_Handler(srv interface{}, ctx context.Context, de
 	if interceptor == nil {
 		return srv.(ExampleServiceServer).GetExample(ctx, in)
 	}
-	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/api.ExampleService/GetExample"}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExampleService_GetExample_FullMethodName,
+	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(ExampleServiceServer).GetExample(ctx, req.(*GetExampleRequest))
 	}
@@ -81,7 +201,10 @@ func _ExampleService_ListExamples_Handler(srv interface{}, ctx context.Context,
 	if interceptor == nil {
 		return srv.(ExampleServiceServer).ListExamples(ctx, in)
 	}
-	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/api.ExampleService/ListExamples"}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExampleService_ListExamples_FullMethodName,
+	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(ExampleServiceServer).ListExamples(ctx, req.(*ListExamplesRequest))
 	}
@@ -96,9 +219,107 @@ func _ExampleService_CreateExample_Handler(srv interface{}, ctx context.Context,
 	if interceptor == nil {
 		return srv.(ExampleServiceServer).CreateExample(ctx, in)
 	}
-	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/api.ExampleService/CreateExample"}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExampleService_CreateExample_FullMethodName,
+	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(ExampleServiceServer).CreateExample(ctx, req.(*CreateExampleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
+
+func _ExampleService_UpdateExample_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateExampleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExampleServiceServer).UpdateExample(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExampleService_UpdateExample_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExampleServiceServer).UpdateExample(ctx, req.(*UpdateExampleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExampleService_DeleteExample_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteExampleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExampleServiceServer).DeleteExample(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExampleService_DeleteExample_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExampleServiceServer).DeleteExample(ctx, req.(*DeleteExampleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExampleService_WatchExamples_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchExamplesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExampleServiceServer).WatchExamples(m, &exampleServiceWatchExamplesServer{stream})
+}
+
+type ExampleService_WatchExamplesServer interface {
+	Send(*ExampleEvent) error
+	grpc.ServerStream
+}
+
+type exampleServiceWatchExamplesServer struct {
+	grpc.ServerStream
+}
+
+func (x *exampleServiceWatchExamplesServer) Send(m *ExampleEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ExampleService_ServiceDesc is the grpc.ServiceDesc for ExampleService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ExampleService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api.ExampleService",
+	HandlerType: (*ExampleServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetExample",
+			Handler:    _ExampleService_GetExample_Handler,
+		},
+		{
+			MethodName: "ListExamples",
+			Handler:    _ExampleService_ListExamples_Handler,
+		},
+		{
+			MethodName: "CreateExample",
+			Handler:    _ExampleService_CreateExample_Handler,
+		},
+		{
+			MethodName: "UpdateExample",
+			Handler:    _ExampleService_UpdateExample_Handler,
+		},
+		{
+			MethodName: "DeleteExample",
+			Handler:    _ExampleService_DeleteExample_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchExamples",
+			Handler:       _ExampleService_WatchExamples_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "example.proto",
+}
@@ -100,11 +100,19 @@ func main() {
 		fmt.Println("  + migration registered in store.go")
 	}
 
+	// Auto-register seeder
+	if err := appendSeeder(data); err != nil {
+		fmt.Fprintf(os.Stderr, "  ! auto-register seeder failed: %v (add manually)\n", err)
+	} else {
+		fmt.Println("  + seeder registered in seed.go")
+	}
+
 	fmt.Printf("\nmodule %s generated successfully!\n", data.PascalName)
 	fmt.Println("\nNext steps:")
 	fmt.Printf("  1. edit internal/model/%s.go — add model fields\n", data.SnakeName)
 	fmt.Printf("  2. edit internal/service/%s_service.go — implement business logic\n", data.SnakeName)
 	fmt.Printf("  3. run make docs — update Swagger docs\n")
+	fmt.Printf("  4. add %s records to configs/seeds/*.yaml (optional)\n", data.PluralName)
 }
 
 func generateFile(tmplPath, outPath string, data ModuleData) error {
@@ -203,6 +211,25 @@ func appendMigration(data ModuleData) error {
 	return os.WriteFile(storeFile, []byte(newContent), 0o644)
 }
 
+// appendSeeder inserts the module's fixture seeder at the marker comment in seed.go
+func appendSeeder(data ModuleData) error {
+	seedFile := "internal/seed/seed.go"
+	content, err := os.ReadFile(seedFile)
+	if err != nil {
+		return err
+	}
+
+	marker := "// GEN:SEED_REGISTER - Auto-appended by code generator, do not remove"
+	seederCode := fmt.Sprintf("Table[model.%s](\"%s\", \"name\"),\n\t\t", data.PascalName, data.PluralName)
+
+	newContent := strings.Replace(string(content), marker, seederCode+marker, 1)
+	if newContent == string(content) {
+		return fmt.Errorf("seeder marker comment not found")
+	}
+
+	return os.WriteFile(seedFile, []byte(newContent), 0o644)
+}
+
 // appendServiceInit prints a reminder to register the service in main.go
 // Note: main.go is not auto-modified because service init may require different params
 
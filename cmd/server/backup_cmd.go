@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"go-api-scaffold/internal/service"
+)
+
+const backupUsage = `Usage:
+  %[1]s backup create [-c file]
+  %[1]s backup list [-c file]
+  %[1]s backup check [-c file]
+  %[1]s backup restore [-c file] <name|file>
+
+Snapshots are written to database.backup.dir. restore accepts the name of a
+snapshot in that directory or the path of any sqlite file; the current data is
+snapshotted first.
+`
+
+// runBackupCommand handles "backup <subcommand>" and returns the exit code
+func runBackupCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, backupUsage, os.Args[0])
+		return 2
+	}
+
+	fs := flag.NewFlagSet("backup "+args[0], flag.ExitOnError)
+	configPath := fs.String("c", "configs/config.yaml", "config file path")
+	pos := parseInterspersed(fs, args[1:])
+
+	switch args[0] {
+	case "create", "list", "check":
+		if len(pos) != 0 {
+			fmt.Fprintf(os.Stderr, backupUsage, os.Args[0])
+			return 2
+		}
+	case "restore":
+		if len(pos) != 1 {
+			fmt.Fprintf(os.Stderr, backupUsage, os.Args[0])
+			return 2
+		}
+	default:
+		fmt.Fprintf(os.Stderr, backupUsage, os.Args[0])
+		return 2
+	}
+
+	cfg, db, err := openStore(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	defer db.Close()
+	if !db.IsSQLite() {
+		fmt.Fprintf(os.Stderr, "backups are only supported for sqlite (database.type is %s)\n", cfg.Database.Type)
+		return 1
+	}
+	backups := service.NewBackupService(db, &cfg.Database)
+	ctx := context.Background()
+
+	switch args[0] {
+	case "create":
+		info, err := backups.Create(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		fmt.Printf("%s (%d bytes)\n", filepath.Join(cfg.Database.Backup.Dir, info.Name), info.Size)
+	case "list":
+		list, err := backups.List()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to list backups: %v\n", err)
+			return 1
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tSIZE\tCREATED")
+		for _, b := range list {
+			fmt.Fprintf(w, "%s\t%d\t%s\n", b.Name, b.Size, b.CreatedAt.Format(time.RFC3339))
+		}
+		_ = w.Flush()
+	case "check":
+		if err := backups.CheckIntegrity(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		fmt.Println("ok")
+	case "restore":
+		// A snapshot in the backup directory, else a path
+		_, statErr := os.Stat(filepath.Join(cfg.Database.Backup.Dir, pos[0]))
+		if statErr == nil && filepath.Base(pos[0]) == pos[0] {
+			err = backups.Restore(ctx, pos[0])
+		} else {
+			err = backups.RestoreFile(ctx, pos[0])
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		fmt.Printf("database restored from %s\n", pos[0])
+	}
+	return 0
+}
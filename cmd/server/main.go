@@ -18,17 +18,22 @@ import (
 	"net"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
+	"path/filepath"
+	"strings"
 
 	"go-api-scaffold/internal/handler"
+	"go-api-scaffold/internal/jobs"
+	"go-api-scaffold/internal/scheduler"
+	"go-api-scaffold/internal/seed"
 	"go-api-scaffold/internal/service"
 	"go-api-scaffold/internal/store"
 	"go-api-scaffold/pkg/config"
+	"go-api-scaffold/pkg/eventbus"
+	"go-api-scaffold/pkg/health"
+	"go-api-scaffold/pkg/lifecycle"
 	"go-api-scaffold/pkg/logger"
-
-	"google.golang.org/grpc"
+	"go-api-scaffold/pkg/metrics"
+	"go-api-scaffold/pkg/tracing"
 )
 
 var (
@@ -37,98 +42,300 @@ var (
 	GitCommit = "unknown"
 )
 
+const usage = `Usage: %[1]s <command> [flags]
+
+Commands:
+  serve [-c file]                                run the HTTP and gRPC servers (default)
+  migrate [-c file]                              create or update the database schema
+  user create [-c file] [-role user|admin] [-generate] <username>
+  user reset-password [-c file] [-generate] <username>
+  user list [-c file]                            manage accounts; passwords are read from stdin
+  config print|validate|schema|encrypt|genkey    inspect the config and encrypt secrets
+  seed [-c file] [-set name] [seeder...]         insert fixtures (set defaults to $APP_ENV, else dev)
+  backup create|list|check [-c file]             snapshot the sqlite database or check its integrity
+  backup restore [-c file] <name|file>           replace the database with a snapshot
+  routes [-c file]                               print the HTTP route table
+  version                                        print version information
+
+Run "%[1]s <command> -h" for the flags of a command.
+`
+
 func main() {
-	// Command line flags
-	configPath := flag.String("c", "configs/config.yaml", "config file path")
-	showVersion := flag.Bool("v", false, "show version")
-	flag.Parse()
+	// Without a command the flags are serve's: "server -c file" still works
+	cmd, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
+	if cmd != "serve" {
+		// Subcommands print results on stdout; store and router logs stay out of it
+		logger.InitCLI()
+	}
+
+	switch cmd {
+	case "serve":
+		os.Exit(runServe(args))
+	case "migrate":
+		os.Exit(runMigrateCommand(args))
+	case "user":
+		os.Exit(runUserCommand(args))
+	case "config":
+		os.Exit(runConfigCommand(args))
+	case "seed":
+		os.Exit(runSeedCommand(args))
+	case "backup":
+		os.Exit(runBackupCommand(args))
+	case "routes":
+		os.Exit(runRoutesCommand(args))
+	case "version":
+		printVersion()
+	case "help":
+		fmt.Printf(usage, os.Args[0])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", cmd)
+		fmt.Fprintf(os.Stderr, usage, os.Args[0])
+		os.Exit(2)
+	}
+}
+
+func printVersion() {
+	fmt.Printf("%s %s\n  Build: %s\n  Commit: %s\n", "my-service", Version, BuildTime, GitCommit)
+}
+
+// runServe runs the servers until SIGINT/SIGTERM and returns the exit code
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	configPath := fs.String("c", "configs/config.yaml", "config file path")
+	showVersion := fs.Bool("v", false, "show version")
+	_ = fs.Parse(args)
 
 	if *showVersion {
-		fmt.Printf("%s %s\n  Build: %s\n  Commit: %s\n", "my-service", Version, BuildTime, GitCommit)
-		os.Exit(0)
+		printVersion()
+		return 0
 	}
 
 	// ====== 1. Load config ======
 	cfg, err := config.Load(*configPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
-		os.Exit(1)
+		return 1
 	}
 
 	// ====== 2. Init logger ======
 	if err := logger.Init(&cfg.Log); err != nil {
 		fmt.Fprintf(os.Stderr, "failed to init logger: %v\n", err)
-		os.Exit(1)
+		return 1
 	}
 	defer logger.Sync()
+	logger.WatchSignals()
 
 	logger.Infof("starting %s %s (build: %s, commit: %s)", cfg.App.Name, Version, BuildTime, GitCommit)
 
+	// Hot reload (subsystems subscribe to the settings they can apply)
+	var reload *config.Watcher
+	if cfg.App.HotReload {
+		reload = config.Watch(*configPath, cfg, logReload)
+		reload.Subscribe(func(c *config.Config) error {
+			logger.Configure(&c.Log)
+			return nil
+		}, "log.level", "log.packages")
+	}
+
 	// ====== 3. Init database ======
 	db, err := store.New(&cfg.Database)
 	if err != nil {
 		logger.Fatalf("failed to init database: %v", err)
 	}
-	defer db.Close()
+
+	// Default admin account (the other seeders run with the seed command)
+	if _, err := seed.Run(context.Background(), &seed.Env{DB: db, Admin: cfg.Admin}, "admin"); err != nil {
+		logger.Errorf("failed to create default admin: %v", err)
+	}
+
+	// Readiness checks (served on /readyz)
+	health.Configure(&cfg.Health)
+	reload.Subscribe(func(c *config.Config) error {
+		health.Configure(&c.Health)
+		return nil
+	}, "health")
+	registerHealthChecks(cfg, db)
+
+	// Metrics (before the servers so interceptors and middleware are wired)
+	if cfg.Metrics.Enabled {
+		metrics.Init(&cfg.Metrics)
+		metrics.SetBuildInfo(Version, GitCommit, BuildTime)
+		if sqlDB, err := db.SQLDB(); err == nil {
+			metrics.RegisterDB(cfg.Database.Type, sqlDB)
+		}
+		logger.Infof("metrics enabled at %s", cfg.Metrics.Path)
+	}
+
+	// Tracing
+	shutdownTracing := func(context.Context) error { return nil }
+	if cfg.Tracing.Enabled {
+		shutdownTracing, err = tracing.Init(&cfg.Tracing, cfg.App.Name, Version)
+		if err != nil {
+			logger.Fatalf("failed to init tracing: %v", err)
+		}
+		logger.Infof("tracing enabled (exporter: %s)", cfg.Tracing.Exporter)
+	}
 
 	// ====== 4. Init service layer ======
 	authSvc := service.NewAuthService(db, cfg.JWT.Secret, cfg.JWT.Expire, cfg.JWT.RefreshHours)
-	exampleSvc := service.NewExampleService(db)
-
-	// ====== 5. Start HTTP server ======
-	r := handler.NewRouter(cfg, authSvc, exampleSvc)
-	httpAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
-	httpServer := &http.Server{
-		Addr:         httpAddr,
-		Handler:      r,
-		ReadTimeout:  time.Duration(cfg.Server.ReadTimeout) * time.Second,
-		WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second,
-	}
-
-	go func() {
-		logger.Infof("HTTP server started: http://%s", httpAddr)
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Fatalf("HTTP server error: %v", err)
-		}
-	}()
-
-	// ====== 6. Start gRPC server (optional) ======
-	var grpcServer *grpc.Server
-	if cfg.GRPC.Enabled {
-		grpcServer = handler.NewGRPCServer(exampleSvc)
-		grpcAddr := fmt.Sprintf(":%d", cfg.GRPC.Port)
-		lis, err := net.Listen("tcp", grpcAddr)
+	bus := eventbus.New(1024)
+	exampleSvc := service.NewExampleService(db, bus)
+
+	// Background jobs and periodic tasks: each module registers its own
+	jobsMgr := jobs.New(db, &cfg.Jobs)
+	exampleSvc.RegisterJobs(jobsMgr)
+	sched := scheduler.New(db, &cfg.Scheduler)
+	jobsMgr.RegisterTasks(sched)
+
+	var backupSvc *service.BackupService
+	if db.IsSQLite() {
+		backupSvc = service.NewBackupService(db, &cfg.Database)
+		health.Register("database_integrity", backupSvc.Integrity)
+	}
+
+	// ====== 5. Init gRPC server (optional, also backs gRPC-Web and the gateway) ======
+	var grpcServer *handler.GRPCServer
+	if cfg.GRPC.Enabled || cfg.GRPC.Web.Enabled || cfg.GRPC.Gateway.Enabled {
+		grpcServer, err = handler.NewGRPCServer(&cfg.GRPC, db, authSvc, exampleSvc)
 		if err != nil {
-			logger.Fatalf("gRPC listen failed: %v", err)
+			logger.Fatalf("failed to init gRPC server: %v", err)
 		}
-		go func() {
-			logger.Infof("gRPC server started: %s", grpcAddr)
-			if err := grpcServer.Serve(lis); err != nil {
-				logger.Fatalf("gRPC server error: %v", err)
-			}
-		}()
+		health.Register("grpc", grpcServer.Check)
 	}
 
-	// ====== 7. Graceful shutdown ======
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	sig := <-quit
-	logger.Infof("received signal: %v, shutting down...", sig)
+	// ====== 6. Init HTTP server ======
+	r := handler.NewRouter(cfg, reload, authSvc, exampleSvc, backupSvc, jobsMgr, sched, grpcServer)
+	httpServer, err := handler.NewHTTPServer(cfg, r, grpcServer)
+	if err != nil {
+		logger.Fatalf("failed to init HTTP server: %v", err)
+	}
 
-	// Stop gRPC
+	// ====== 7. Run: components start in order and stop in reverse ======
+	app := lifecycle.New(&cfg.Shutdown)
+	reload.Subscribe(func(c *config.Config) error {
+		app.Configure(&c.Shutdown)
+		return nil
+	}, "shutdown")
+
+	// Both still run past the shutdown deadline
+	app.Append(lifecycle.Hook{Name: "database", Final: true, Stop: func(context.Context) error {
+		db.Close()
+		return nil
+	}})
+	// Flush pending spans
+	app.Append(lifecycle.Hook{Name: "tracing", Final: true, Stop: shutdownTracing})
+	// Scheduled snapshots and integrity checks
+	if backupSvc != nil {
+		app.Append(lifecycle.Hook{Name: "backup", Start: backupSvc.Start, Stop: backupSvc.Stop})
+	}
+	// Workers stop after the servers, so jobs enqueued by draining requests are
+	// kept; running jobs get the "jobs" drain timeout, then go back to the queue
+	app.Append(lifecycle.Hook{Name: "jobs", Start: jobsMgr.Start, Stop: jobsMgr.Stop})
+	app.Append(lifecycle.Hook{Name: "scheduler", Start: sched.Start, Stop: sched.Stop})
 	if grpcServer != nil {
-		grpcServer.GracefulStop()
-		logger.Info("gRPC server stopped")
+		app.Append(grpcHook(cfg, grpcServer, app))
 	}
+	app.Append(httpHook(httpServer, app))
+	// End watch streams first so draining servers don't wait on them
+	app.Append(lifecycle.Hook{Name: "events", Stop: func(context.Context) error {
+		bus.Close()
+		return nil
+	}})
 
-	// Stop HTTP (5s timeout)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := httpServer.Shutdown(ctx); err != nil {
-		logger.Errorf("HTTP server shutdown error: %v", err)
-	} else {
-		logger.Info("HTTP server stopped")
+	if err := app.Run(context.Background()); err != nil {
+		logger.Errorf("service exited with error: %v", err)
+		return 1
 	}
-
 	logger.Info("service exited")
+	return 0
+}
+
+// httpHook serves HTTP. Shutdown also drains gRPC-Web calls, which gRPC's
+// GracefulStop cannot drain itself, so it stops before gRPC.
+func httpHook(srv *http.Server, app *lifecycle.Manager) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name: "http",
+		Start: func(context.Context) error {
+			lis, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return err
+			}
+			go func() {
+				if srv.TLSConfig != nil {
+					logger.Infof("HTTP server started: https://%s", srv.Addr)
+					err = srv.ServeTLS(lis, "", "")
+				} else {
+					logger.Infof("HTTP server started: http://%s", srv.Addr)
+					err = srv.Serve(lis)
+				}
+				if err != nil && err != http.ErrServerClosed {
+					app.Fail(fmt.Errorf("HTTP server: %w", err))
+				}
+			}()
+			return nil
+		},
+		Stop: srv.Shutdown,
+	}
+}
+
+// grpcHook serves gRPC on its dedicated port unless it shares the HTTP port
+// or only backs gRPC-Web
+func grpcHook(cfg *config.Config, s *handler.GRPCServer, app *lifecycle.Manager) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name: "grpc",
+		Start: func(context.Context) error {
+			if cfg.GRPC.Enabled && cfg.GRPC.SharePort {
+				logger.Infof("gRPC server sharing HTTP port: %d (reflection: %v)", cfg.Server.Port, cfg.GRPC.Reflection)
+				return nil
+			}
+			if !cfg.GRPC.Enabled {
+				return nil
+			}
+			grpcAddr := fmt.Sprintf(":%d", cfg.GRPC.Port)
+			lis, err := net.Listen("tcp", grpcAddr)
+			if err != nil {
+				return err
+			}
+			go func() {
+				logger.Infof("gRPC server started: %s (tls: %v, reflection: %v)", grpcAddr, cfg.GRPC.TLS.Enabled, cfg.GRPC.Reflection)
+				if err := s.Serve(lis); err != nil {
+					app.Fail(fmt.Errorf("gRPC server: %w", err))
+				}
+			}()
+			return nil
+		},
+		Stop: s.Shutdown,
+	}
+}
+
+// registerHealthChecks registers the database ping and the disk space checks
+// of the volumes the service writes to
+func registerHealthChecks(cfg *config.Config, db *store.Store) {
+	health.Register("database", db.Ping)
+	if cfg.Database.Type == "sqlite" {
+		health.Register("disk_database", health.DiskSpace(filepath.Dir(cfg.Database.Path)))
+	}
+	if cfg.Log.Output != "console" && cfg.Log.FilePath != "" {
+		health.Register("disk_log", health.DiskSpace(filepath.Dir(cfg.Log.FilePath)))
+	}
+}
+
+// logReload reports the outcome of a config file reload
+func logReload(r config.ReloadReport) {
+	if len(r.Applied) > 0 {
+		logger.Infof("config reloaded: %s", strings.Join(r.Applied, ", "))
+	}
+	if len(r.RestartRequired) > 0 {
+		logger.Warnf("config changes require a restart: %s", strings.Join(r.RestartRequired, ", "))
+	}
+	if r.Err != nil {
+		if r.Changed == nil {
+			logger.Errorf("config reload failed, keeping the active config: %v", r.Err)
+		} else {
+			logger.Errorf("config reload: %v", r.Err)
+		}
+	}
 }
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"go-api-scaffold/internal/store"
+	"go-api-scaffold/pkg/config"
+)
+
+// openStore loads the config and connects to the database without migrating it
+func openStore(configPath string) (*config.Config, *store.Store, error) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg.Database.AutoMigrate = false
+	db, err := store.New(&cfg.Database)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, db, nil
+}
+
+// runMigrateCommand handles "migrate" and returns the exit code
+func runMigrateCommand(args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	configPath := fs.String("c", "configs/config.yaml", "config file path")
+	_ = fs.Parse(args)
+
+	cfg, db, err := openStore(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	if err := db.AutoMigrate(); err != nil {
+		fmt.Fprintf(os.Stderr, "migration failed: %v\n", err)
+		return 1
+	}
+	fmt.Printf("database schema is up to date (%s)\n", cfg.Database.Type)
+	return 0
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"go-api-scaffold/internal/handler"
+	"go-api-scaffold/internal/jobs"
+	"go-api-scaffold/internal/scheduler"
+	"go-api-scaffold/internal/service"
+	"go-api-scaffold/pkg/eventbus"
+
+	"github.com/gin-gonic/gin"
+)
+
+// runRoutesCommand handles "routes" and returns the exit code
+func runRoutesCommand(args []string) int {
+	fs := flag.NewFlagSet("routes", flag.ExitOnError)
+	configPath := fs.String("c", "configs/config.yaml", "config file path")
+	_ = fs.Parse(args)
+
+	cfg, db, err := openStore(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	// The router is built as serve builds it, without serving
+	authSvc := service.NewAuthService(db, cfg.JWT.Secret, cfg.JWT.Expire, cfg.JWT.RefreshHours)
+	bus := eventbus.New(1)
+	defer bus.Close()
+	exampleSvc := service.NewExampleService(db, bus)
+	jobsMgr := jobs.New(db, &cfg.Jobs)
+	exampleSvc.RegisterJobs(jobsMgr)
+	sched := scheduler.New(db, &cfg.Scheduler)
+	var backupSvc *service.BackupService
+	if db.IsSQLite() {
+		backupSvc = service.NewBackupService(db, &cfg.Database)
+	}
+	var grpcServer *handler.GRPCServer
+	if cfg.GRPC.Enabled || cfg.GRPC.Web.Enabled || cfg.GRPC.Gateway.Enabled {
+		grpcServer, err = handler.NewGRPCServer(&cfg.GRPC, db, authSvc, exampleSvc)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to init gRPC server: %v\n", err)
+			return 1
+		}
+		defer grpcServer.Stop()
+	}
+	gin.DefaultWriter = io.Discard
+	r := handler.NewRouter(cfg, nil, authSvc, exampleSvc, backupSvc, jobsMgr, sched, grpcServer)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tHANDLER")
+	routes := r.Routes()
+	sort.SliceStable(routes, func(i, j int) bool { return routes[i].Path < routes[j].Path })
+	for _, route := range routes {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", route.Method, route.Path, strings.TrimPrefix(route.Handler, "go-api-scaffold/internal/"))
+	}
+	_ = w.Flush()
+	return 0
+}
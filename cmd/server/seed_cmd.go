@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go-api-scaffold/internal/seed"
+	"go-api-scaffold/pkg/config"
+)
+
+// runSeedCommand handles "seed" and returns the exit code
+func runSeedCommand(args []string) int {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	configPath := fs.String("c", "configs/config.yaml", "config file path")
+	set := fs.String("set", defaultSeedSet(), "seed set: <dir>/<set>.yaml")
+	dir := fs.String("dir", "", "fixtures directory (default: seeds/ next to the config file)")
+	list := fs.Bool("list", false, "list the seeders and exit")
+	names := parseInterspersed(fs, args)
+
+	if *list {
+		for _, s := range seed.Seeders() {
+			fmt.Println(s.Name)
+		}
+		return 0
+	}
+	if *dir == "" {
+		*dir = filepath.Join(filepath.Dir(*configPath), "seeds")
+	}
+
+	fixtures, err := seed.LoadFixtures(*dir, *set)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	cfg, db, err := openStore(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	reports, err := seed.Run(context.Background(), &seed.Env{DB: db, Admin: cfg.Admin, Fixtures: fixtures}, names...)
+	for _, r := range reports {
+		fmt.Printf("%-12s %d created, %d existing\n", r.Name, r.Created, r.Existing)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// defaultSeedSet follows the config layer selected by APP_ENV
+func defaultSeedSet() string {
+	if env := os.Getenv(config.EnvVar); env != "" {
+		return env
+	}
+	return "dev"
+}
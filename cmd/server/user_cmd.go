@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"go-api-scaffold/internal/model"
+	"go-api-scaffold/internal/service"
+)
+
+const userUsage = `Usage:
+  %[1]s user create [-c file] [-role user|admin] [-generate] <username>
+  %[1]s user reset-password [-c file] [-generate] <username>
+  %[1]s user list [-c file]
+
+The password is read from stdin (echo -n 'secret' | %[1]s user create alice),
+or generated and printed with -generate.
+`
+
+// runUserCommand handles "user <subcommand>" and returns the exit code
+func runUserCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, userUsage, os.Args[0])
+		return 2
+	}
+
+	fs := flag.NewFlagSet("user "+args[0], flag.ExitOnError)
+	configPath := fs.String("c", "configs/config.yaml", "config file path")
+	role := fs.String("role", model.RoleUser, "role of the new user: user, admin")
+	generate := fs.Bool("generate", false, "generate a random password and print it")
+	pos := parseInterspersed(fs, args[1:])
+
+	var username string
+	switch args[0] {
+	case "create", "reset-password":
+		if len(pos) != 1 {
+			fmt.Fprintf(os.Stderr, userUsage, os.Args[0])
+			return 2
+		}
+		username = pos[0]
+	case "list":
+	default:
+		fmt.Fprintf(os.Stderr, userUsage, os.Args[0])
+		return 2
+	}
+
+	_, db, err := openStore(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	defer db.Close()
+	users := service.NewUserService(db)
+	ctx := context.Background()
+
+	if args[0] == "list" {
+		list, err := users.List(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to list users: %v\n", err)
+			return 1
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tUSERNAME\tROLE\tCREATED")
+		for _, u := range list {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", u.ID, u.Username, u.Role, u.CreatedAt.Format(time.RFC3339))
+		}
+		_ = w.Flush()
+		return 0
+	}
+
+	password, err := readPassword(*generate)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if args[0] == "create" {
+		_, err = users.Create(ctx, username, password, *role)
+	} else {
+		err = users.ResetPassword(ctx, username, password)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %s: %v\n", args[0], username, err)
+		return 1
+	}
+	if *generate {
+		fmt.Printf("password: %s\n", password)
+	}
+	if args[0] == "create" {
+		fmt.Printf("user %s created (role: %s)\n", username, *role)
+	} else {
+		fmt.Printf("password of %s reset\n", username)
+	}
+	return 0
+}
+
+// readPassword generates a password or reads one from stdin
+func readPassword(generate bool) (string, error) {
+	if generate {
+		b := make([]byte, 18)
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+		return base64.RawURLEncoding.EncodeToString(b), nil
+	}
+	if fi, err := os.Stdin.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		return "", errors.New("pipe the password to stdin or use -generate")
+	}
+	password, err := readValue(nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return password, nil
+}
+
+// parseInterspersed parses fs from args, allowing flags after positional
+// arguments ("user create alice -role admin"), and returns the positionals
+func parseInterspersed(fs *flag.FlagSet, args []string) []string {
+	var pos []string
+	for {
+		_ = fs.Parse(args)
+		if fs.NArg() == 0 {
+			return pos
+		}
+		pos = append(pos, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"go-api-scaffold/pkg/health"
+	"go-api-scaffold/pkg/logger"
+	"go-api-scaffold/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler handles operational endpoints (admin role only)
+type AdminHandler struct{}
+
+func NewAdminHandler() *AdminHandler {
+	return &AdminHandler{}
+}
+
+// LogLevelResponse is the current log level state
+type LogLevelResponse struct {
+	Level    string            `json:"level"`    // current global level
+	Default  string            `json:"default"`  // configured level
+	Packages map[string]string `json:"packages"` // per-package overrides
+}
+
+// SetLogLevelRequest changes log levels; omitted fields are unchanged
+type SetLogLevelRequest struct {
+	Level    string            `json:"level" binding:"omitempty,oneof=debug info warn error default"` // "default" restores the configured level
+	Packages map[string]string `json:"packages"`                                                      // "" removes an override
+}
+
+// GetLogLevel returns the runtime log levels
+// @Summary  Get log level
+// @Tags     Admin
+// @Security Bearer
+// @Produce  json
+// @Success  200 {object} response.Response{data=LogLevelResponse}
+// @Router   /admin/log-level [get]
+func (h *AdminHandler) GetLogLevel(c *gin.Context) {
+	response.Success(c, currentLogLevel())
+}
+
+// SetLogLevel changes the log levels without a restart
+// @Summary  Set log level
+// @Tags     Admin
+// @Security Bearer
+// @Accept   json
+// @Produce  json
+// @Param    body body SetLogLevelRequest true "Levels"
+// @Success  200  {object} response.Response{data=LogLevelResponse}
+// @Router   /admin/log-level [put]
+func (h *AdminHandler) SetLogLevel(c *gin.Context) {
+	var req SetLogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters: "+err.Error())
+		return
+	}
+
+	switch req.Level {
+	case "":
+	case "default":
+		logger.ResetLevel()
+	default:
+		if err := logger.SetLevel(req.Level); err != nil {
+			response.ParamError(c, err.Error())
+			return
+		}
+	}
+	for pkg, level := range req.Packages {
+		if err := logger.SetPackageLevel(pkg, level); err != nil {
+			response.ParamError(c, err.Error())
+			return
+		}
+	}
+
+	state := currentLogLevel()
+	logger.FromContext(c.Request.Context()).Warnw("log level changed", "level", state.Level, "packages", state.Packages)
+	response.Success(c, state)
+}
+
+// GetHealth returns every readiness check with its error and timing
+// @Summary  Get dependency health
+// @Tags     Admin
+// @Security Bearer
+// @Produce  json
+// @Success  200 {object} response.Response{data=health.Report}
+// @Router   /admin/health [get]
+func (h *AdminHandler) GetHealth(c *gin.Context) {
+	response.Success(c, health.Ready(c.Request.Context()))
+}
+
+func currentLogLevel() LogLevelResponse {
+	return LogLevelResponse{
+		Level:    logger.Level(),
+		Default:  logger.DefaultLevel(),
+		Packages: logger.PackageLevels(),
+	}
+}
@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"context"
 	"strings"
 
 	"go-api-scaffold/internal/service"
+	"go-api-scaffold/pkg/logger"
 	"go-api-scaffold/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -39,7 +41,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.authSvc.Login(req.Username, req.Password)
+	token, err := h.authSvc.Login(c.Request.Context(), req.Username, req.Password)
 	if err != nil {
 		response.Unauthorized(c, err.Error())
 		return
@@ -62,7 +64,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	token, err := h.authSvc.RefreshToken(tokenStr)
+	token, err := h.authSvc.RefreshToken(c.Request.Context(), tokenStr)
 	if err != nil {
 		response.Unauthorized(c, err.Error())
 		return
@@ -115,6 +117,8 @@ func AuthMiddleware(authSvc *service.AuthService) gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		ctx := context.WithValue(c.Request.Context(), claimsCtxKey{}, claims)
+		c.Request = c.Request.WithContext(logger.With(ctx, "user_id", claims.UserID, "username", claims.Username))
 		c.Next()
 	}
 }
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+
+	"go-api-scaffold/internal/service"
+	"go-api-scaffold/pkg/logger"
+	"go-api-scaffold/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BackupHandler handles sqlite snapshot endpoints (admin role only)
+type BackupHandler struct {
+	svc *service.BackupService
+}
+
+func NewBackupHandler(svc *service.BackupService) *BackupHandler {
+	return &BackupHandler{svc: svc}
+}
+
+// List returns the snapshots, newest first
+// @Summary  List database backups
+// @Tags     Admin
+// @Security Bearer
+// @Produce  json
+// @Success  200 {object} response.Response{data=[]service.BackupInfo}
+// @Router   /admin/backups [get]
+func (h *BackupHandler) List(c *gin.Context) {
+	list, err := h.svc.List()
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Errorw("list backups failed", "error", err)
+		response.ServerError(c, "list backups failed")
+		return
+	}
+	response.Success(c, list)
+}
+
+// Create takes a snapshot of the live database
+// @Summary  Create database backup
+// @Tags     Admin
+// @Security Bearer
+// @Produce  json
+// @Success  200 {object} response.Response{data=service.BackupInfo}
+// @Router   /admin/backups [post]
+func (h *BackupHandler) Create(c *gin.Context) {
+	info, err := h.svc.Create(c.Request.Context())
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Errorw("backup failed", "error", err)
+		response.ServerError(c, "backup failed: "+err.Error())
+		return
+	}
+	logger.FromContext(c.Request.Context()).Infow("database backup created", "name", info.Name, "size", info.Size)
+	response.Success(c, info)
+}
+
+// Restore replaces the live database with a snapshot. The current data is
+// snapshotted first.
+// @Summary  Restore database backup
+// @Tags     Admin
+// @Security Bearer
+// @Produce  json
+// @Param    name path string true "Backup file name"
+// @Success  200 {object} response.Response
+// @Router   /admin/backups/{name}/restore [post]
+func (h *BackupHandler) Restore(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.svc.Restore(c.Request.Context(), name); err != nil {
+		if errors.Is(err, service.ErrBackupNotFound) {
+			response.NotFound(c, "backup not found")
+			return
+		}
+		logger.FromContext(c.Request.Context()).Errorw("restore failed", "name", name, "error", err)
+		response.ServerError(c, "restore failed: "+err.Error())
+		return
+	}
+	logger.FromContext(c.Request.Context()).Warnw("database restored", "name", name)
+	response.OK(c)
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"go-api-scaffold/pkg/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// corsPolicy is a CORSConfig prepared for request matching
+type corsPolicy struct {
+	anyOrigin     bool
+	origins       map[string]bool
+	wildcards     []wildcardOrigin
+	methods       map[string]bool
+	anyHeader     bool
+	headers       map[string]bool
+	allowMethods  string
+	allowHeaders  string
+	exposeHeaders string
+	credentials   bool
+	maxAge        string
+}
+
+// wildcardOrigin matches scheme://*.domain[:port]
+type wildcardOrigin struct {
+	prefix string // "https://"
+	suffix string // ".example.com"
+}
+
+type corsRoute struct {
+	prefix string
+	policy *corsPolicy
+}
+
+// corsPolicies are the base policy and the overrides, longest prefix first
+type corsPolicies struct {
+	base   *corsPolicy
+	routes []corsRoute
+}
+
+// CORS handles Cross-Origin Resource Sharing.
+// The policy is chosen by the longest matching cors.overrides path_prefix.
+// Changes to the cors section are applied on config reload.
+func CORS(cfg *config.CORSConfig, reload *config.Watcher) gin.HandlerFunc {
+	var current atomic.Pointer[corsPolicies]
+	current.Store(newCORSPolicies(cfg))
+	reload.Subscribe(func(c *config.Config) error {
+		current.Store(newCORSPolicies(&c.CORS))
+		return nil
+	}, "cors")
+
+	return func(c *gin.Context) {
+		policies := current.Load()
+		policy := policies.base
+		for _, r := range policies.routes {
+			if strings.HasPrefix(c.Request.URL.Path, r.prefix) {
+				policy = r.policy
+				break
+			}
+		}
+		policy.handle(c)
+	}
+}
+
+func newCORSPolicies(cfg *config.CORSConfig) *corsPolicies {
+	routes := make([]corsRoute, 0, len(cfg.Overrides))
+	for _, o := range cfg.Overrides {
+		p := cfg.Policy(o)
+		routes = append(routes, corsRoute{prefix: o.PathPrefix, policy: newCORSPolicy(&p)})
+	}
+	sort.SliceStable(routes, func(i, j int) bool { return len(routes[i].prefix) > len(routes[j].prefix) })
+	return &corsPolicies{base: newCORSPolicy(cfg), routes: routes}
+}
+
+func newCORSPolicy(cfg *config.CORSConfig) *corsPolicy {
+	p := &corsPolicy{
+		origins:       make(map[string]bool),
+		methods:       make(map[string]bool),
+		headers:       make(map[string]bool),
+		allowMethods:  strings.Join(cfg.AllowMethods, ", "),
+		exposeHeaders: strings.Join(cfg.ExposeHeaders, ", "),
+		credentials:   cfg.AllowCredentials,
+		maxAge:        strconv.Itoa(cfg.MaxAge),
+	}
+	for _, o := range cfg.AllowOrigins {
+		o = strings.ToLower(o)
+		switch {
+		case o == "*":
+			p.anyOrigin = true
+		case strings.Contains(o, "://*."):
+			i := strings.Index(o, "*")
+			p.wildcards = append(p.wildcards, wildcardOrigin{prefix: o[:i], suffix: o[i+1:]})
+		default:
+			p.origins[o] = true
+		}
+	}
+	for _, m := range cfg.AllowMethods {
+		p.methods[strings.ToUpper(m)] = true
+	}
+	for _, h := range cfg.AllowHeaders {
+		if h == "*" {
+			p.anyHeader = true
+			continue
+		}
+		p.headers[http.CanonicalHeaderKey(h)] = true
+	}
+	if !p.anyHeader {
+		p.allowHeaders = strings.Join(cfg.AllowHeaders, ", ")
+	}
+	return p
+}
+
+func (p *corsPolicy) allowOrigin(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if p.origins[origin] {
+		return true
+	}
+	for _, w := range p.wildcards {
+		// The wildcard needs at least one subdomain label
+		if strings.HasPrefix(origin, w.prefix) && strings.HasSuffix(origin, w.suffix) &&
+			len(origin) > len(w.prefix)+len(w.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *corsPolicy) handle(c *gin.Context) {
+	origin := c.GetHeader("Origin")
+	preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+	// Responses differ per origin unless every origin gets "*"
+	if !p.anyOrigin || p.credentials {
+		c.Writer.Header().Add("Vary", "Origin")
+	}
+	if preflight {
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+	}
+
+	if origin == "" {
+		c.Next()
+		return
+	}
+	if !p.allowOrigin(origin) {
+		if preflight {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		// Not a CORS-approved origin: serve without CORS headers, the browser blocks the read
+		c.Next()
+		return
+	}
+
+	if !preflight {
+		p.writeOrigin(c, origin)
+		if p.exposeHeaders != "" {
+			c.Header("Access-Control-Expose-Headers", p.exposeHeaders)
+		}
+		c.Next()
+		return
+	}
+
+	// Preflight: reject methods and headers outside the policy
+	if !p.methods[strings.ToUpper(c.GetHeader("Access-Control-Request-Method"))] {
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+	requested := c.GetHeader("Access-Control-Request-Headers")
+	allowHeaders := p.allowHeaders
+	if p.anyHeader {
+		allowHeaders = requested
+	} else {
+		for _, h := range strings.Split(requested, ",") {
+			if h = strings.TrimSpace(h); h != "" && !p.headers[http.CanonicalHeaderKey(h)] {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+		}
+	}
+
+	p.writeOrigin(c, origin)
+	c.Header("Access-Control-Allow-Methods", p.allowMethods)
+	if allowHeaders != "" {
+		c.Header("Access-Control-Allow-Headers", allowHeaders)
+	}
+	if p.maxAge != "0" {
+		c.Header("Access-Control-Max-Age", p.maxAge)
+	}
+	c.AbortWithStatus(http.StatusNoContent)
+}
+
+func (p *corsPolicy) writeOrigin(c *gin.Context, origin string) {
+	if p.anyOrigin && !p.credentials {
+		c.Header("Access-Control-Allow-Origin", "*")
+	} else {
+		c.Header("Access-Control-Allow-Origin", origin)
+	}
+	if p.credentials {
+		c.Header("Access-Control-Allow-Credentials", "true")
+	}
+}
@@ -1,13 +1,16 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 
 	"go-api-scaffold/internal/model"
 	"go-api-scaffold/internal/service"
+	"go-api-scaffold/pkg/logger"
 	"go-api-scaffold/pkg/response"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // ExampleHandler handles Example CRUD endpoints
@@ -36,8 +39,9 @@ func (h *ExampleHandler) List(c *gin.Context) {
 		return
 	}
 
-	items, total, err := h.svc.List(&req)
+	items, total, err := h.svc.List(c.Request.Context(), &req)
 	if err != nil {
+		logger.FromContext(c.Request.Context()).Errorw("list query failed", "error", err)
 		response.ServerError(c, "query failed")
 		return
 	}
@@ -61,7 +65,7 @@ func (h *ExampleHandler) Create(c *gin.Context) {
 		return
 	}
 
-	item, err := h.svc.Create(&req)
+	item, err := h.svc.Create(c.Request.Context(), &req)
 	if err != nil {
 		response.ServerError(c, "create failed: "+err.Error())
 		return
@@ -70,6 +74,33 @@ func (h *ExampleHandler) Create(c *gin.Context) {
 	response.Success(c, item)
 }
 
+// Import creates examples in a background job; poll GET /jobs/{id} for the outcome
+// @Summary  Import examples
+// @Tags     Example
+// @Security Bearer
+// @Accept   json
+// @Produce  json
+// @Param    body body model.ImportExampleRequest true "Examples to create"
+// @Success  200  {object} response.Response{data=model.Job}
+// @Router   /examples/import [post]
+func (h *ExampleHandler) Import(c *gin.Context) {
+	var req model.ImportExampleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters: "+err.Error())
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	job, err := h.svc.Import(c.Request.Context(), &req, userID)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Errorw("enqueue import failed", "error", err)
+		response.ServerError(c, "import failed")
+		return
+	}
+
+	response.Success(c, job)
+}
+
 // Get returns an example by ID
 // @Summary  Get example by ID
 // @Tags     Example
@@ -84,7 +115,7 @@ func (h *ExampleHandler) Get(c *gin.Context) {
 		return
 	}
 
-	item, err := h.svc.GetByID(uint(id))
+	item, err := h.svc.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
 		response.NotFound(c, "record not found")
 		return
@@ -115,7 +146,7 @@ func (h *ExampleHandler) Update(c *gin.Context) {
 		return
 	}
 
-	item, err := h.svc.Update(uint(id), &req)
+	item, err := h.svc.Update(c.Request.Context(), uint(id), &req)
 	if err != nil {
 		response.ServerError(c, "update failed: "+err.Error())
 		return
@@ -138,7 +169,12 @@ func (h *ExampleHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.svc.Delete(uint(id)); err != nil {
+	err = h.svc.Delete(c.Request.Context(), uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		response.NotFound(c, "record not found")
+		return
+	}
+	if err != nil {
 		response.ServerError(c, "delete failed: "+err.Error())
 		return
 	}
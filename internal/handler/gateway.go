@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	pb "go-api-scaffold/api/proto/gen"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// NewGateway serves proto services as JSON/HTTP according to their google.api.http
+// annotations. Calls go to grpcServer over a loopback connection, through the
+// same interceptors as native gRPC calls; the Authorization header and the
+// request ID are passed on as metadata. Streaming methods are not transcoded.
+func NewGateway(grpcServer *GRPCServer) (http.Handler, error) {
+	ctx := context.Background()
+	conn, err := grpcServer.DialLoopback(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := runtime.NewServeMux(
+		runtime.WithMetadata(func(ctx context.Context, _ *http.Request) metadata.MD {
+			if requestID, ok := ctx.Value(requestIDCtxKey{}).(string); ok {
+				return metadata.Pairs(requestIDKey, requestID)
+			}
+			return nil
+		}),
+		// snake_case fields and zero values, matching the REST API's JSON
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				UseProtoNames:   true,
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		}),
+	)
+
+	if err := pb.RegisterExampleServiceHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+
+	return mux, nil
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"go-api-scaffold/internal/service"
+	"go-api-scaffold/pkg/logger"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// claimsCtxKey holds the claims of an authenticated call in its context
+type claimsCtxKey struct{}
+
+// grpcPublicPrefixes are the methods served without a token: health checks
+// (probes) and reflection
+var grpcPublicPrefixes = []string{"/grpc.health.v1.", "/grpc.reflection."}
+
+// authUnaryInterceptor requires a valid JWT in the authorization metadata,
+// as AuthMiddleware does for HTTP
+func authUnaryInterceptor(authSvc *service.AuthService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, authSvc, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authStreamInterceptor is authUnaryInterceptor for streams
+func authStreamInterceptor(authSvc *service.AuthService) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), authSvc, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate validates the bearer token of a call. gRPC-Web calls were
+// already authenticated by AuthMiddleware and carry its claims.
+func authenticate(ctx context.Context, authSvc *service.AuthService, method string) (context.Context, error) {
+	for _, prefix := range grpcPublicPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return ctx, nil
+		}
+	}
+	if _, ok := ctx.Value(claimsCtxKey{}).(*service.Claims); ok {
+		return ctx, nil
+	}
+
+	var tokenStr string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) > 0 {
+			if parts := strings.SplitN(v[0], " ", 2); len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
+				tokenStr = parts[1]
+			}
+		}
+	}
+	if tokenStr == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing authentication token")
+	}
+	claims, err := authSvc.ValidateToken(tokenStr)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	}
+
+	ctx = context.WithValue(ctx, claimsCtxKey{}, claims)
+	return logger.With(ctx, "user_id", claims.UserID, "username", claims.Username), nil
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	pb "go-api-scaffold/api/proto/gen"
+	"go-api-scaffold/internal/model"
+	"go-api-scaffold/internal/service"
+	"go-api-scaffold/internal/store"
+	"go-api-scaffold/pkg/config"
+	"go-api-scaffold/pkg/eventbus"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestGRPC serves a gRPC server over an in-memory listener and returns a
+// client connection and a valid token
+func newTestGRPC(t *testing.T) (*grpc.ClientConn, string) {
+	t.Helper()
+	db, err := store.New(&config.DatabaseConfig{Type: "sqlite", Path: filepath.Join(t.TempDir(), "grpc.db"), AutoMigrate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(db.Close)
+	ctx := context.Background()
+	if _, err := service.NewUserService(db).Create(ctx, "alice", "alice-pass", model.RoleUser); err != nil {
+		t.Fatal(err)
+	}
+	authSvc := service.NewAuthService(db, "test-secret", 1, 1)
+	token, err := authSvc.Login(ctx, "alice", "alice-pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bus := eventbus.New(16)
+	t.Cleanup(bus.Close)
+	s, err := NewGRPCServer(&config.GRPCConfig{}, db, authSvc, service.NewExampleService(db, bus))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn, token.Token
+}
+
+func TestGRPCRequiresToken(t *testing.T) {
+	conn, token := newTestGRPC(t)
+	client := pb.NewExampleServiceClient(conn)
+
+	calls := map[string]func(ctx context.Context) error{
+		"create": func(ctx context.Context) error {
+			_, err := client.CreateExample(ctx, &pb.CreateExampleRequest{Name: "x"})
+			return err
+		},
+		"update": func(ctx context.Context) error {
+			_, err := client.UpdateExample(ctx, &pb.UpdateExampleRequest{Id: 1})
+			return err
+		},
+		"delete": func(ctx context.Context) error {
+			_, err := client.DeleteExample(ctx, &pb.DeleteExampleRequest{Id: 1})
+			return err
+		},
+		"watch": func(ctx context.Context) error {
+			stream, err := client.WatchExamples(ctx, &pb.WatchExamplesRequest{})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			for _, ctx := range []context.Context{
+				context.Background(),
+				metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer invalid"),
+				metadata.AppendToOutgoingContext(context.Background(), "authorization", token), // no scheme
+			} {
+				if err := call(ctx); status.Code(err) != codes.Unauthenticated {
+					t.Errorf("got %v, want Unauthenticated", err)
+				}
+			}
+		})
+	}
+
+	t.Run("with token", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+		created, err := client.CreateExample(ctx, &pb.CreateExampleRequest{Name: "x"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.DeleteExample(ctx, &pb.DeleteExampleRequest{Id: created.GetId()}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("health is public", func(t *testing.T) {
+		if _, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
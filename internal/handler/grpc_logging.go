@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+
+	"go-api-scaffold/pkg/logger"
+	"go-api-scaffold/pkg/tracing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// requestIDKey is the metadata key carrying the request ID (same as the HTTP header)
+const requestIDKey = "x-request-id"
+
+// loggingUnaryInterceptor attaches a request-scoped logger to the handler context
+func loggingUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, requestID := withRequestLogger(ctx, info.FullMethod)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+		return handler(ctx, req)
+	}
+}
+
+// loggingStreamInterceptor attaches a request-scoped logger to the stream context
+func loggingStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, requestID := withRequestLogger(ss.Context(), info.FullMethod)
+		_ = ss.SetHeader(metadata.Pairs(requestIDKey, requestID))
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// withRequestLogger reuses the request ID from metadata (or generates one).
+// gRPC-Web calls already carry the HTTP request logger, including the user identity.
+func withRequestLogger(ctx context.Context, method string) (context.Context, string) {
+	if requestID, ok := ctx.Value(requestIDCtxKey{}).(string); ok {
+		return logger.With(ctx, "grpc_method", method), requestID
+	}
+
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(requestIDKey); len(v) > 0 {
+			requestID = v[0]
+		}
+	}
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+
+	fields := []interface{}{"request_id", requestID, "grpc_method", method}
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		fields = append(fields, "trace_id", traceID)
+	}
+	ctx = context.WithValue(ctx, requestIDCtxKey{}, requestID)
+	return logger.With(ctx, fields...), requestID
+}
+
+// contextStream overrides the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -2,45 +2,285 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	pb "go-api-scaffold/api/proto/gen"
 	"go-api-scaffold/internal/model"
 	"go-api-scaffold/internal/service"
-	pb "go-api-scaffold/api/proto/gen"
+	"go-api-scaffold/internal/store"
+	"go-api-scaffold/pkg/config"
+	"go-api-scaffold/pkg/eventbus"
+	"go-api-scaffold/pkg/logger"
+	"go-api-scaffold/pkg/metrics"
+	"go-api-scaffold/pkg/tracing"
 
+	"github.com/gin-gonic/gin/binding"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
 )
 
+// grpcHealthInterval is how often database liveness is reported to the health service
+const grpcHealthInterval = 10 * time.Second
+
+// GRPCServer wraps grpc.Server with the standard grpc.health.v1 service
+type GRPCServer struct {
+	*grpc.Server
+	health   *health.Server
+	db       *store.Store
+	done     chan struct{}
+	stopOnce sync.Once
+
+	served  atomic.Bool // Serve was called (dedicated port)
+	serving atomic.Bool // Serve is running
+
+	httpMu     sync.Mutex
+	httpClosed bool           // Shutdown started: calls over HTTP are refused
+	httpCalls  sync.WaitGroup // calls served over HTTP (shared port, gRPC-Web, gateway)
+	loopback   *http.Server   // serves DialLoopback connections
+}
+
 // ExampleGRPCServer implements the gRPC service
 type ExampleGRPCServer struct {
 	pb.UnimplementedExampleServiceServer
 	svc *service.ExampleService
 }
 
-// NewGRPCServer creates and registers gRPC services
-func NewGRPCServer(exampleSvc *service.ExampleService) *grpc.Server {
-	s := grpc.NewServer()
+// NewGRPCServer creates and registers gRPC services. Calls other than health
+// checks and reflection need a JWT in the authorization metadata.
+func NewGRPCServer(cfg *config.GRPCConfig, db *store.Store, authSvc *service.AuthService, exampleSvc *service.ExampleService) (*GRPCServer, error) {
+	var opts []grpc.ServerOption
+	if cfg.TLS.Enabled {
+		tlsCfg, err := newTLSConfig(&cfg.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("grpc tls: %w", err)
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+	}
+	if tracing.Enabled() {
+		opts = append(opts, grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	}
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(loggingUnaryInterceptor()),
+		grpc.ChainStreamInterceptor(loggingStreamInterceptor()),
+	)
+	if metrics.Enabled() {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(metrics.UnaryServerInterceptor()),
+			grpc.ChainStreamInterceptor(metrics.StreamServerInterceptor()),
+		)
+	}
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(authUnaryInterceptor(authSvc)),
+		grpc.ChainStreamInterceptor(authStreamInterceptor(authSvc)),
+	)
+
+	s := grpc.NewServer(opts...)
 
 	pb.RegisterExampleServiceServer(s, &ExampleGRPCServer{svc: exampleSvc})
 	// GEN:GRPC_REGISTER - Auto-appended by code generator, do not remove
 
-	return s
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(s, hs)
+
+	if cfg.Reflection {
+		reflection.Register(s)
+	}
+
+	gs := &GRPCServer{
+		Server: s,
+		health: hs,
+		db:     db,
+		done:   make(chan struct{}),
+	}
+	gs.checkHealth()
+	go gs.watchHealth()
+
+	return gs, nil
+}
+
+// Serve accepts connections on lis until the server stops
+func (s *GRPCServer) Serve(lis net.Listener) error {
+	s.served.Store(true)
+	s.serving.Store(true)
+	defer s.serving.Store(false)
+	return s.Server.Serve(lis)
+}
+
+// ServeHTTP serves a call received by the HTTP server (shared port or
+// gRPC-Web). grpc.Server cannot drain these connections, so they are counted
+// for Shutdown to wait on, and refused with UNAVAILABLE once it has started.
+func (s *GRPCServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.httpMu.Lock()
+	if s.httpClosed {
+		s.httpMu.Unlock()
+		h := w.Header()
+		h.Set("Content-Type", "application/grpc")
+		h.Set(http2.TrailerPrefix+"Grpc-Status", strconv.Itoa(int(codes.Unavailable)))
+		h.Set(http2.TrailerPrefix+"Grpc-Message", encodeGRPCMessage("server is shutting down"))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	s.httpCalls.Add(1)
+	s.httpMu.Unlock()
+	defer s.httpCalls.Done()
+	s.Server.ServeHTTP(w, r)
+}
+
+// DialLoopback connects an in-process client (the gateway) to the server
+// through a loopback h2c listener served like the shared port, so its calls
+// pass the same interceptors (auth, logging, metrics, tracing) as remote ones
+func (s *GRPCServer) DialLoopback(ctx context.Context) (*grpc.ClientConn, error) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	srv := &http.Server{Handler: h2c.NewHandler(s, &http2.Server{}), ReadHeaderTimeout: 10 * time.Second}
+	s.httpMu.Lock()
+	s.loopback = srv
+	s.httpMu.Unlock()
+	go func() { _ = srv.Serve(lis) }()
+
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if tracing.Enabled() {
+		opts = append(opts, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	}
+	return grpc.DialContext(ctx, lis.Addr().String(), opts...)
+}
+
+// Check reports whether the server is accepting calls (readiness check)
+func (s *GRPCServer) Check(context.Context) error {
+	select {
+	case <-s.done:
+		return errors.New("stopped")
+	default:
+	}
+	if s.served.Load() && !s.serving.Load() {
+		return errors.New("listener closed")
+	}
+	return nil
+}
+
+// GracefulStop marks all services NOT_SERVING, then waits for pending RPCs
+func (s *GRPCServer) GracefulStop() {
+	s.shutdownHealth()
+	s.Server.GracefulStop()
+}
+
+// Stop marks all services NOT_SERVING and closes all connections immediately
+func (s *GRPCServer) Stop() {
+	s.shutdownHealth()
+	s.closeLoopback()
+	s.Server.Stop()
+}
+
+// Shutdown stops gracefully, closing the remaining connections when ctx expires.
+// New calls over HTTP are refused and the running ones waited for first, as
+// grpc.Server.GracefulStop does not support them.
+func (s *GRPCServer) Shutdown(ctx context.Context) error {
+	s.shutdownHealth()
+	s.httpMu.Lock()
+	s.httpClosed = true
+	s.httpMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.httpCalls.Wait()
+		s.closeLoopback()
+		s.Server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.closeLoopback()
+		s.Server.Stop()
+		return fmt.Errorf("forced stop: %w", ctx.Err())
+	}
+}
+
+func (s *GRPCServer) closeLoopback() {
+	s.httpMu.Lock()
+	defer s.httpMu.Unlock()
+	if s.loopback != nil {
+		_ = s.loopback.Close()
+	}
+}
+
+func (s *GRPCServer) shutdownHealth() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+		s.health.Shutdown()
+	})
+}
+
+// watchHealth periodically reports database liveness to the health service
+func (s *GRPCServer) watchHealth() {
+	ticker := time.NewTicker(grpcHealthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.checkHealth()
+		}
+	}
+}
+
+// checkHealth sets the overall ("") and per-service status from a database ping
+func (s *GRPCServer) checkHealth() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	st := healthpb.HealthCheckResponse_SERVING
+	if err := s.db.Ping(ctx); err != nil {
+		logger.Warnf("gRPC health: database ping failed: %v", err)
+		st = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	s.health.SetServingStatus("", st)
+	for name := range s.GetServiceInfo() {
+		// Skip built-in services (health, reflection)
+		if strings.HasPrefix(name, "grpc.") {
+			continue
+		}
+		s.health.SetServingStatus(name, st)
+	}
 }
 
 // GetExample returns an example by ID
 func (s *ExampleGRPCServer) GetExample(ctx context.Context, req *pb.GetExampleRequest) (*pb.ExampleResponse, error) {
-	item, err := s.svc.GetByID(uint(req.Id))
-	if err != nil {
+	item, err := s.svc.GetByID(ctx, uint(req.Id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, status.Errorf(codes.NotFound, "not found: %v", err)
 	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get failed: %v", err)
+	}
 
-	return &pb.ExampleResponse{
-		Id:          uint64(item.ID),
-		Name:        item.Name,
-		Description: item.Description,
-		Status:      item.Status,
-	}, nil
+	return toExamplePB(item), nil
 }
 
 // ListExamples returns a paginated list
@@ -49,21 +289,17 @@ func (s *ExampleGRPCServer) ListExamples(ctx context.Context, req *pb.ListExampl
 		Page:     int(req.Page),
 		PageSize: int(req.PageSize),
 		Keyword:  req.Keyword,
+		Status:   req.Status,
 	}
 
-	items, total, err := s.svc.List(query)
+	items, total, err := s.svc.List(ctx, query)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "list failed: %v", err)
 	}
 
 	pbItems := make([]*pb.ExampleResponse, len(items))
-	for i, item := range items {
-		pbItems[i] = &pb.ExampleResponse{
-			Id:          uint64(item.ID),
-			Name:        item.Name,
-			Description: item.Description,
-			Status:      item.Status,
-		}
+	for i := range items {
+		pbItems[i] = toExamplePB(&items[i])
 	}
 
 	return &pb.ListExamplesResponse{
@@ -74,18 +310,108 @@ func (s *ExampleGRPCServer) ListExamples(ctx context.Context, req *pb.ListExampl
 
 // CreateExample creates a new example
 func (s *ExampleGRPCServer) CreateExample(ctx context.Context, req *pb.CreateExampleRequest) (*pb.ExampleResponse, error) {
-	item, err := s.svc.Create(&model.CreateExampleRequest{
+	createReq := &model.CreateExampleRequest{
 		Name:        req.Name,
 		Description: req.Description,
-	})
+		Status:      req.Status,
+	}
+	if err := binding.Validator.ValidateStruct(createReq); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid parameters: %v", err)
+	}
+
+	item, err := s.svc.Create(ctx, createReq)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "create failed: %v", err)
 	}
 
+	return toExamplePB(item), nil
+}
+
+// UpdateExample updates the fields present in the request
+func (s *ExampleGRPCServer) UpdateExample(ctx context.Context, req *pb.UpdateExampleRequest) (*pb.ExampleResponse, error) {
+	updateReq := &model.UpdateExampleRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Status:      req.Status,
+	}
+	if err := binding.Validator.ValidateStruct(updateReq); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid parameters: %v", err)
+	}
+
+	item, err := s.svc.Update(ctx, uint(req.Id), updateReq)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Errorf(codes.NotFound, "not found: %v", err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "update failed: %v", err)
+	}
+
+	return toExamplePB(item), nil
+}
+
+// DeleteExample removes an example
+func (s *ExampleGRPCServer) DeleteExample(ctx context.Context, req *pb.DeleteExampleRequest) (*emptypb.Empty, error) {
+	err := s.svc.Delete(ctx, uint(req.Id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Errorf(codes.NotFound, "not found: %v", err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "delete failed: %v", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// WatchExamples streams example changes, optionally resuming after a revision
+func (s *ExampleGRPCServer) WatchExamples(req *pb.WatchExamplesRequest, stream pb.ExampleService_WatchExamplesServer) error {
+	sub, err := s.svc.Watch(req.SinceRevision)
+	if errors.Is(err, eventbus.ErrRevisionUnavailable) {
+		return status.Errorf(codes.OutOfRange, "revision %d unavailable, relist and watch from 0", req.SinceRevision)
+	}
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "watch failed: %v", err)
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case ev, ok := <-sub.C:
+			if !ok {
+				if errors.Is(sub.Err(), eventbus.ErrSlowConsumer) {
+					return status.Error(codes.ResourceExhausted, "watcher fell behind, resume from last revision")
+				}
+				return status.Error(codes.Unavailable, "watch closed")
+			}
+			if err := stream.Send(toExampleEventPB(ev)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func toExamplePB(item *model.Example) *pb.ExampleResponse {
 	return &pb.ExampleResponse{
 		Id:          uint64(item.ID),
 		Name:        item.Name,
 		Description: item.Description,
 		Status:      item.Status,
-	}, nil
+		CreatedAt:   timestamppb.New(item.CreatedAt),
+		UpdatedAt:   timestamppb.New(item.UpdatedAt),
+	}
+}
+
+var exampleEventTypes = map[string]pb.ExampleEvent_Type{
+	service.EventCreated: pb.ExampleEvent_CREATED,
+	service.EventUpdated: pb.ExampleEvent_UPDATED,
+	service.EventDeleted: pb.ExampleEvent_DELETED,
+}
+
+func toExampleEventPB(ev eventbus.Event) *pb.ExampleEvent {
+	return &pb.ExampleEvent{
+		Type:       exampleEventTypes[ev.Type],
+		Revision:   ev.Revision,
+		Example:    toExamplePB(ev.Payload.(*model.Example)),
+		OccurredAt: timestamppb.New(ev.Time),
+	}
 }
@@ -0,0 +1,536 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-api-scaffold/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// gRPC-Web and Connect bridge
+//
+// Browser requests are translated into native gRPC requests and served by the
+// same grpc.Server in-process, so every service, interceptor and handler is shared:
+//   - gRPC-Web: application/grpc-web[+proto] and application/grpc-web-text[+proto],
+//     unary and server-streaming
+//   - Connect: unary calls with application/proto or application/json bodies
+
+const grpcFrameTrailer = 0x80
+
+// GRPCWebHandler serves gRPC-Web and Connect requests for a gRPC server
+type GRPCWebHandler struct {
+	server  *GRPCServer
+	connect bool
+}
+
+// NewGRPCWebHandler creates the bridge; connect enables the Connect unary protocol
+func NewGRPCWebHandler(server *GRPCServer, connect bool) *GRPCWebHandler {
+	return &GRPCWebHandler{server: server, connect: connect}
+}
+
+// Services returns the service names exposed to browsers (built-in grpc.* services excluded)
+func (h *GRPCWebHandler) Services() []string {
+	var names []string
+	for name := range h.server.GetServiceInfo() {
+		if !strings.HasPrefix(name, "grpc.") {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Handle is the gin handler for POST /<package.Service>/:method
+func (h *GRPCWebHandler) Handle(c *gin.Context) {
+	if rw, ok := c.Writer.(*rejectionWriter); ok {
+		c.Writer = rw.ResponseWriter
+	}
+	ct := c.GetHeader("Content-Type")
+	switch {
+	case strings.HasPrefix(ct, "application/grpc-web-text"):
+		h.serveGRPCWeb(c.Writer, c.Request, true)
+	case strings.HasPrefix(ct, "application/grpc-web"):
+		h.serveGRPCWeb(c.Writer, c.Request, false)
+	case h.isConnect(ct):
+		h.serveConnectUnary(c.Writer, c.Request, strings.HasPrefix(ct, "application/json"))
+	default:
+		c.AbortWithStatus(http.StatusUnsupportedMediaType)
+	}
+}
+
+func (h *GRPCWebHandler) isConnect(contentType string) bool {
+	return h.connect && (contentType == "application/proto" || contentType == "application/json" ||
+		strings.HasPrefix(contentType, "application/json;"))
+}
+
+// Rejections goes before the middlewares of the bridged routes (authentication,
+// rate limiting) and converts the JSON errors they reply with into gRPC-Web or
+// Connect errors, so browser clients see UNAUTHENTICATED or RESOURCE_EXHAUSTED
+// instead of a protocol error
+func (h *GRPCWebHandler) Rejections() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rw := &rejectionWriter{ResponseWriter: c.Writer}
+		c.Writer = rw
+		c.Next()
+		if c.Writer != rw {
+			return // reached Handle
+		}
+		c.Writer = rw.ResponseWriter
+		if rw.status == 0 {
+			return
+		}
+
+		var body response.Response
+		_ = json.Unmarshal(rw.body.Bytes(), &body)
+		code := grpcCodeFromHTTP(rw.status)
+		ct := c.GetHeader("Content-Type")
+		switch {
+		case strings.HasPrefix(ct, "application/grpc-web"):
+			writeGRPCWebError(c.Writer, strings.HasPrefix(ct, "application/grpc-web-text"), code, body.Message)
+		case h.isConnect(ct):
+			writeConnectError(c.Writer, code, body.Message)
+		default:
+			c.Writer.WriteHeader(rw.status)
+			_, _ = c.Writer.Write(rw.body.Bytes())
+		}
+	}
+}
+
+// rejectionWriter holds back the response of a middleware that rejected a bridged call
+type rejectionWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *rejectionWriter) WriteHeader(code int) { w.status = code }
+func (w *rejectionWriter) WriteHeaderNow()      {}
+func (w *rejectionWriter) Written() bool        { return w.status != 0 }
+func (w *rejectionWriter) Status() int          { return w.status }
+
+func (w *rejectionWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(p)
+}
+
+func (w *rejectionWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// grpcCodeFromHTTP maps the HTTP status of a rejected call to a gRPC code
+func grpcCodeFromHTTP(status int) codes.Code {
+	switch status {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.Unimplemented
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Unknown
+	}
+}
+
+// ========================
+// gRPC-Web
+// ========================
+
+func (h *GRPCWebHandler) serveGRPCWeb(w http.ResponseWriter, r *http.Request, text bool) {
+	req := toGRPCRequest(r, "application/grpc+proto")
+	if text {
+		req.Body = io.NopCloser(base64.NewDecoder(base64.StdEncoding, r.Body))
+	}
+
+	contentType := "application/grpc-web+proto"
+	if text {
+		contentType = "application/grpc-web-text+proto"
+	}
+
+	gw := &grpcWebWriter{w: w, header: make(http.Header), contentType: contentType, text: text}
+	h.server.ServeHTTP(gw, req)
+	gw.finish()
+}
+
+// grpcWebWriter converts a native gRPC response into gRPC-Web:
+// trailers are sent as a final length-prefixed frame in the body.
+type grpcWebWriter struct {
+	w           http.ResponseWriter
+	header      http.Header
+	contentType string
+	text        bool
+	wroteHeader bool
+	buf         bytes.Buffer // pending bytes for base64 chunking
+}
+
+func (g *grpcWebWriter) Header() http.Header { return g.header }
+
+func (g *grpcWebWriter) WriteHeader(code int) {
+	if g.wroteHeader {
+		return
+	}
+	g.wroteHeader = true
+
+	dst := g.w.Header()
+	for k, vv := range g.header {
+		if k == "Trailer" || strings.HasPrefix(k, http2.TrailerPrefix) {
+			continue
+		}
+		dst[k] = vv
+	}
+	dst.Set("Content-Type", g.contentType)
+	dst.Del("Content-Length")
+	g.w.WriteHeader(code)
+}
+
+func (g *grpcWebWriter) Write(p []byte) (int, error) {
+	g.WriteHeader(http.StatusOK)
+	if g.text {
+		return g.buf.Write(p)
+	}
+	return g.w.Write(p)
+}
+
+func (g *grpcWebWriter) Flush() {
+	if !g.wroteHeader {
+		// gRPC flushes before writing status; headers go out with the first write
+		return
+	}
+	if g.text && g.buf.Len() > 0 {
+		enc := make([]byte, base64.StdEncoding.EncodedLen(g.buf.Len()))
+		base64.StdEncoding.Encode(enc, g.buf.Bytes())
+		g.buf.Reset()
+		_, _ = g.w.Write(enc)
+	}
+	if f, ok := g.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// finish writes the trailer frame once the gRPC handler has returned
+func (g *grpcWebWriter) finish() {
+	var trailer bytes.Buffer
+	for _, k := range g.header.Values("Trailer") {
+		for _, v := range g.header.Values(k) {
+			fmt.Fprintf(&trailer, "%s: %s\r\n", strings.ToLower(k), v)
+		}
+	}
+	for k, vv := range g.header {
+		if !strings.HasPrefix(k, http2.TrailerPrefix) {
+			continue
+		}
+		name := strings.ToLower(strings.TrimPrefix(k, http2.TrailerPrefix))
+		for _, v := range vv {
+			fmt.Fprintf(&trailer, "%s: %s\r\n", name, v)
+		}
+	}
+
+	frame := make([]byte, 5, 5+trailer.Len())
+	frame[0] = grpcFrameTrailer
+	binary.BigEndian.PutUint32(frame[1:], uint32(trailer.Len()))
+	frame = append(frame, trailer.Bytes()...)
+
+	_, _ = g.Write(frame)
+	g.Flush()
+}
+
+// writeGRPCWebError replies to a call that never reached the gRPC server with
+// a response holding only the trailer frame
+func writeGRPCWebError(w http.ResponseWriter, text bool, code codes.Code, message string) {
+	contentType := "application/grpc-web+proto"
+	if text {
+		contentType = "application/grpc-web-text+proto"
+	}
+	gw := &grpcWebWriter{w: w, header: make(http.Header), contentType: contentType, text: text}
+	gw.header.Set(http2.TrailerPrefix+"Grpc-Status", strconv.Itoa(int(code)))
+	gw.header.Set(http2.TrailerPrefix+"Grpc-Message", encodeGRPCMessage(message))
+	gw.finish()
+}
+
+// ========================
+// Connect (unary)
+// ========================
+
+func (h *GRPCWebHandler) serveConnectUnary(w http.ResponseWriter, r *http.Request, useJSON bool) {
+	method, err := lookupMethod(r.URL.Path)
+	if err != nil {
+		writeConnectError(w, codes.Unimplemented, err.Error())
+		return
+	}
+	if method.IsStreamingClient() || method.IsStreamingServer() {
+		writeConnectError(w, codes.Unimplemented, "streaming methods require gRPC-Web")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeConnectError(w, codes.InvalidArgument, "read request: "+err.Error())
+		return
+	}
+	if useJSON {
+		if body, err = transcodeJSON(method.Input(), body); err != nil {
+			writeConnectError(w, codes.InvalidArgument, "invalid request: "+err.Error())
+			return
+		}
+	}
+
+	frame := make([]byte, 5, 5+len(body))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(body)))
+	frame = append(frame, body...)
+
+	req := toGRPCRequest(r, "application/grpc+proto")
+	req.Body = io.NopCloser(bytes.NewReader(frame))
+	req.ContentLength = int64(len(frame))
+	if ms := r.Header.Get("Connect-Timeout-Ms"); ms != "" {
+		req.Header.Set("Grpc-Timeout", ms+"m")
+	}
+
+	rec := &responseRecorder{header: make(http.Header)}
+	h.server.ServeHTTP(rec, req)
+
+	code := codes.Unknown
+	if v, err := strconv.Atoi(rec.header.Get("Grpc-Status")); err == nil {
+		code = codes.Code(v)
+	}
+	if code != codes.OK {
+		writeConnectError(w, code, decodeGRPCMessage(rec.header.Get("Grpc-Message")))
+		return
+	}
+
+	msg := rec.body.Bytes()
+	if len(msg) < 5 || uint64(len(msg)) < 5+uint64(binary.BigEndian.Uint32(msg[1:5])) {
+		writeConnectError(w, codes.Internal, "malformed response")
+		return
+	}
+	msg = msg[5 : 5+binary.BigEndian.Uint32(msg[1:5])]
+
+	contentType := "application/proto"
+	if useJSON {
+		contentType = "application/json"
+		if msg, err = transcodeProto(method.Output(), msg); err != nil {
+			writeConnectError(w, codes.Internal, err.Error())
+			return
+		}
+	}
+
+	// Response metadata: headers as-is, trailers with a Trailer- prefix
+	for k, vv := range rec.header {
+		switch {
+		case strings.HasPrefix(k, http2.TrailerPrefix):
+			w.Header()["Trailer-"+strings.TrimPrefix(k, http2.TrailerPrefix)] = vv
+		case k == "Content-Type" || k == "Trailer" || strings.HasPrefix(k, "Grpc-"):
+		default:
+			w.Header()[k] = vv
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(msg)
+}
+
+// lookupMethod resolves /package.Service/Method to its descriptor
+func lookupMethod(path string) (protoreflect.MethodDescriptor, error) {
+	svc, name, ok := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid method path %q", path)
+	}
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(svc))
+	if err != nil {
+		return nil, fmt.Errorf("unknown service %s", svc)
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("unknown service %s", svc)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(name))
+	if md == nil {
+		return nil, fmt.Errorf("unknown method %s/%s", svc, name)
+	}
+	return md, nil
+}
+
+// transcodeJSON converts a JSON message body into protobuf binary
+func transcodeJSON(desc protoreflect.MessageDescriptor, data []byte) ([]byte, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+	if err != nil {
+		return nil, err
+	}
+	msg := mt.New().Interface()
+	if len(data) > 0 {
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, msg); err != nil {
+			return nil, err
+		}
+	}
+	return proto.Marshal(msg)
+}
+
+// transcodeProto converts a protobuf binary message into JSON
+func transcodeProto(desc protoreflect.MessageDescriptor, data []byte) ([]byte, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+	if err != nil {
+		return nil, err
+	}
+	msg := mt.New().Interface()
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, err
+	}
+	return protojson.Marshal(msg)
+}
+
+// writeConnectError writes a Connect unary error: an HTTP status plus {"code","message"}
+func writeConnectError(w http.ResponseWriter, code codes.Code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(connectHTTPStatus(code))
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"code":    connectCodeName(code),
+		"message": message,
+	})
+}
+
+func connectCodeName(code codes.Code) string {
+	switch code {
+	case codes.Canceled:
+		return "canceled"
+	case codes.InvalidArgument:
+		return "invalid_argument"
+	case codes.DeadlineExceeded:
+		return "deadline_exceeded"
+	case codes.NotFound:
+		return "not_found"
+	case codes.AlreadyExists:
+		return "already_exists"
+	case codes.PermissionDenied:
+		return "permission_denied"
+	case codes.ResourceExhausted:
+		return "resource_exhausted"
+	case codes.FailedPrecondition:
+		return "failed_precondition"
+	case codes.Aborted:
+		return "aborted"
+	case codes.OutOfRange:
+		return "out_of_range"
+	case codes.Unimplemented:
+		return "unimplemented"
+	case codes.Internal:
+		return "internal"
+	case codes.Unavailable:
+		return "unavailable"
+	case codes.DataLoss:
+		return "data_loss"
+	case codes.Unauthenticated:
+		return "unauthenticated"
+	default:
+		return "unknown"
+	}
+}
+
+// connectHTTPStatus maps gRPC codes to HTTP statuses per the Connect protocol
+func connectHTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// encodeGRPCMessage percent-encodes the grpc-message header
+func encodeGRPCMessage(msg string) string {
+	var b strings.Builder
+	for i := 0; i < len(msg); i++ {
+		if c := msg[i]; c >= ' ' && c <= '~' && c != '%' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// decodeGRPCMessage reverses the percent-encoding of the grpc-message header
+func decodeGRPCMessage(msg string) string {
+	var b strings.Builder
+	for i := 0; i < len(msg); i++ {
+		if msg[i] == '%' && i+2 < len(msg) {
+			if v, err := strconv.ParseUint(msg[i+1:i+3], 16, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(msg[i])
+	}
+	return b.String()
+}
+
+// ========================
+// Helpers
+// ========================
+
+// toGRPCRequest clones r as an HTTP/2 gRPC request accepted by grpc.Server.ServeHTTP
+func toGRPCRequest(r *http.Request, contentType string) *http.Request {
+	req := r.Clone(r.Context())
+	req.ProtoMajor, req.ProtoMinor, req.Proto = 2, 0, "HTTP/2.0"
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	// Make the gRPC server span a child of the HTTP span
+	otel.GetTextMapPropagator().Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+	return req
+}
+
+// responseRecorder buffers a complete gRPC response in memory
+type responseRecorder struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header         { return r.header }
+func (r *responseRecorder) WriteHeader(int)             {}
+func (r *responseRecorder) Write(p []byte) (int, error) { return r.body.Write(p) }
+func (r *responseRecorder) Flush()                      {}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"go-api-scaffold/pkg/health"
+	"go-api-scaffold/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Livez reports that the process is up and serving HTTP. It does not check
+// dependencies: a failing database should take the pod out of rotation, not restart it.
+func Livez(c *gin.Context) {
+	response.Success(c, gin.H{"status": health.StatusOK})
+}
+
+// Readyz runs the dependency checks; 503 when any fails or the service is shutting down.
+// Only check statuses are public, errors and timings are on /api/v1/admin/health.
+func Readyz(c *gin.Context) {
+	rep := health.Ready(c.Request.Context())
+	checks := make(map[string]string, len(rep.Checks))
+	for name, res := range rep.Checks {
+		checks[name] = res.Status
+	}
+	data := gin.H{"status": rep.Status, "checks": checks}
+	if !rep.OK() {
+		response.Unavailable(c, "not ready", data)
+		return
+	}
+	response.Success(c, data)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-api-scaffold/pkg/config"
+
+	"golang.org/x/net/http2"
+)
+
+// NewHTTPServer creates the HTTP server for the router.
+// When grpcServer is non-nil and grpc.share_port is set, gRPC is served on the same listener.
+func NewHTTPServer(cfg *config.Config, router http.Handler, grpcServer *GRPCServer) (*http.Server, error) {
+	handler := router
+	writeTimeout := time.Duration(cfg.Server.WriteTimeout) * time.Second
+	var h2s *http2.Server
+	if grpcServer != nil && cfg.GRPC.Enabled && cfg.GRPC.SharePort {
+		if !cfg.Server.TLS.Enabled {
+			h2s = &http2.Server{}
+		}
+		handler = NewMixedHandler(router, grpcServer, h2s)
+	}
+	if grpcServer != nil && (cfg.GRPC.SharePort || cfg.GRPC.Web.Enabled) {
+		// A write deadline would cut off long-lived gRPC(-Web) streams;
+		// REST requests are still bounded by the Timeout middleware.
+		writeTimeout = 0
+	}
+
+	srv := &http.Server{
+		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
+		Handler:      handler,
+		ReadTimeout:  time.Duration(cfg.Server.ReadTimeout) * time.Second,
+		WriteTimeout: writeTimeout,
+	}
+
+	if cfg.Server.TLS.Enabled {
+		tlsCfg, err := newTLSConfig(&cfg.Server.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("server tls: %w", err)
+		}
+		srv.TLSConfig = tlsCfg
+	}
+	if h2s != nil {
+		// h2c connections are hijacked, so Shutdown does not see them;
+		// registering h2s makes it send them GOAWAY. The TLS config it sets
+		// is not wanted on a plaintext listener.
+		if err := http2.ConfigureServer(srv, h2s); err != nil {
+			return nil, fmt.Errorf("server h2c: %w", err)
+		}
+		srv.TLSConfig = nil
+	}
+
+	return srv, nil
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"go-api-scaffold/internal/jobs"
+	"go-api-scaffold/internal/model"
+	"go-api-scaffold/pkg/logger"
+	"go-api-scaffold/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobHandler handles background job endpoints
+type JobHandler struct {
+	jobs *jobs.Manager
+}
+
+func NewJobHandler(m *jobs.Manager) *JobHandler {
+	return &JobHandler{jobs: m}
+}
+
+// Get returns the status of a job enqueued by the current user (any job for admins)
+// @Summary  Get job status
+// @Tags     Job
+// @Security Bearer
+// @Param    id path int true "Job ID"
+// @Success  200 {object} response.Response{data=model.Job}
+// @Router   /jobs/{id} [get]
+func (h *JobHandler) Get(c *gin.Context) {
+	job, ok := h.find(c)
+	if !ok {
+		return
+	}
+	if c.GetString("role") != model.RoleAdmin && c.GetUint("user_id") != job.CreatedBy {
+		// Other users' jobs are reported as missing
+		response.NotFound(c, "job not found")
+		return
+	}
+	response.Success(c, job)
+}
+
+// List returns a paginated list of jobs
+// @Summary  List jobs
+// @Tags     Admin
+// @Security Bearer
+// @Param    page      query int    false "Page number" default(1)
+// @Param    page_size query int    false "Page size"   default(10)
+// @Param    queue     query string false "Queue"
+// @Param    type      query string false "Job type"
+// @Param    status    query string false "Status" Enums(pending, running, succeeded, dead, cancelled)
+// @Success  200 {object} response.Response{data=response.PageData}
+// @Router   /admin/jobs [get]
+func (h *JobHandler) List(c *gin.Context) {
+	var req model.QueryJobRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.ParamError(c, "invalid parameters: "+err.Error())
+		return
+	}
+
+	items, total, err := h.jobs.List(c.Request.Context(), &req)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Errorw("list jobs failed", "error", err)
+		response.ServerError(c, "query failed")
+		return
+	}
+	response.SuccessPage(c, items, total, req.Page, req.PageSize)
+}
+
+// Stats returns the job counts by queue and status
+// @Summary  Job queue statistics
+// @Tags     Admin
+// @Security Bearer
+// @Success  200 {object} response.Response{data=[]model.JobStats}
+// @Router   /admin/jobs/stats [get]
+func (h *JobHandler) Stats(c *gin.Context) {
+	stats, err := h.jobs.Stats(c.Request.Context())
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Errorw("job stats failed", "error", err)
+		response.ServerError(c, "query failed")
+		return
+	}
+	response.Success(c, stats)
+}
+
+// AdminGet returns any job
+// @Summary  Get job
+// @Tags     Admin
+// @Security Bearer
+// @Param    id path int true "Job ID"
+// @Success  200 {object} response.Response{data=model.Job}
+// @Router   /admin/jobs/{id} [get]
+func (h *JobHandler) AdminGet(c *gin.Context) {
+	if job, ok := h.find(c); ok {
+		response.Success(c, job)
+	}
+}
+
+// Retry requeues a dead-lettered or cancelled job with fresh attempts
+// @Summary  Retry job
+// @Tags     Admin
+// @Security Bearer
+// @Param    id path int true "Job ID"
+// @Success  200 {object} response.Response{data=model.Job}
+// @Router   /admin/jobs/{id}/retry [post]
+func (h *JobHandler) Retry(c *gin.Context) {
+	h.transition(c, "retry", h.jobs.Retry)
+}
+
+// Cancel cancels a pending job
+// @Summary  Cancel job
+// @Tags     Admin
+// @Security Bearer
+// @Param    id path int true "Job ID"
+// @Success  200 {object} response.Response{data=model.Job}
+// @Router   /admin/jobs/{id}/cancel [post]
+func (h *JobHandler) Cancel(c *gin.Context) {
+	h.transition(c, "cancel", h.jobs.Cancel)
+}
+
+func (h *JobHandler) transition(c *gin.Context, action string, fn func(ctx context.Context, id uint) (*model.Job, error)) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	job, err := fn(c.Request.Context(), uint(id))
+	switch {
+	case errors.Is(err, jobs.ErrNotFound):
+		response.NotFound(c, "job not found")
+	case errors.Is(err, jobs.ErrState):
+		response.Conflict(c, err.Error())
+	case err != nil:
+		logger.FromContext(c.Request.Context()).Errorw("job "+action+" failed", "id", id, "error", err)
+		response.ServerError(c, action+" failed")
+	default:
+		logger.FromContext(c.Request.Context()).Infow("job "+action, "id", id, "type", job.Type)
+		response.Success(c, job)
+	}
+}
+
+// find loads the job of the :id parameter, responding on failure
+func (h *JobHandler) find(c *gin.Context) (*model.Job, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return nil, false
+	}
+
+	job, err := h.jobs.Get(c.Request.Context(), uint(id))
+	if errors.Is(err, jobs.ErrNotFound) {
+		response.NotFound(c, "job not found")
+		return nil, false
+	}
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Errorw("get job failed", "id", id, "error", err)
+		response.ServerError(c, "query failed")
+		return nil, false
+	}
+	return job, true
+}
@@ -2,15 +2,25 @@ package handler
 
 import (
 	"context"
+	"math/rand"
 	"net/http"
 	"strings"
 	"time"
 
+	"go-api-scaffold/pkg/config"
 	"go-api-scaffold/pkg/logger"
+	"go-api-scaffold/pkg/metrics"
 	"go-api-scaffold/pkg/response"
+	"go-api-scaffold/pkg/tracing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 )
 
 // Recovery handles panics and returns 500
@@ -18,7 +28,7 @@ func Recovery() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				logger.Errorf("[Recovery] panic: %v", err)
+				logger.FromContext(c.Request.Context()).Errorf("[Recovery] panic: %v", err)
 				response.ServerError(c, "internal server error")
 				c.Abort()
 			}
@@ -27,21 +37,8 @@ func Recovery() gin.HandlerFunc {
 	}
 }
 
-// CORS handles Cross-Origin Resource Sharing
-func CORS() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Request-ID")
-		c.Header("Access-Control-Expose-Headers", "X-Request-ID, X-Total-Count")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusNoContent)
-			return
-		}
-		c.Next()
-	}
-}
+// requestIDCtxKey holds the request ID in the request context
+type requestIDCtxKey struct{}
 
 // RequestID adds a unique request ID
 func RequestID() gin.HandlerFunc {
@@ -52,6 +49,14 @@ func RequestID() gin.HandlerFunc {
 		}
 		c.Set("X-Request-ID", requestID)
 		c.Header("X-Request-ID", requestID)
+
+		// Request-scoped logger: logger.FromContext(ctx) in handlers, services and repositories
+		fields := []interface{}{"request_id", requestID}
+		if traceID := tracing.TraceID(c.Request.Context()); traceID != "" {
+			fields = append(fields, "trace_id", traceID)
+		}
+		ctx := context.WithValue(c.Request.Context(), requestIDCtxKey{}, requestID)
+		c.Request = c.Request.WithContext(logger.With(ctx, fields...))
 		c.Next()
 	}
 }
@@ -67,15 +72,142 @@ func Logger() gin.HandlerFunc {
 
 		// Only log 4xx/5xx or slow requests (>1s)
 		if status >= 400 || latency > time.Second {
-			logger.Warnf("[%d] %s %s %s %v",
+			logger.FromContext(c.Request.Context()).Warnf("[%d] %s %s %s %v",
 				status, c.Request.Method, c.Request.URL.Path, c.ClientIP(), latency)
 		}
 	}
 }
 
-// Timeout sets a deadline on the request context.
+// Tracing starts a server span per request, continuing the caller's W3C traceparent.
+// The trace ID is returned in the X-Trace-ID header.
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		ctx, span := tracing.Tracer().Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(c.Request.URL.Path),
+				semconv.ClientAddress(c.ClientIP()),
+			),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		if traceID := tracing.TraceID(ctx); traceID != "" {
+			c.Header("X-Trace-ID", traceID)
+		}
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if len(c.Errors) > 0 {
+			span.RecordError(c.Errors.Last())
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
+// Metrics records request count and latency by route template
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		done := metrics.HTTPStart()
+		c.Next()
+		done()
+
+		// Label by template, never the raw path, to keep cardinality bounded
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveHTTP(route, c.Request.Method, c.Writer.Status(), time.Since(start))
+	}
+}
+
+// AccessLog writes one structured entry per request.
+// Errors (>= 400) and slow requests are always logged; successful ones are sampled.
+func AccessLog(log *zap.Logger, cfg *config.AccessLogConfig) gin.HandlerFunc {
+	fields := make(map[string]bool, len(config.AccessLogFields))
+	if len(cfg.Fields) == 0 {
+		for _, f := range config.AccessLogFields {
+			fields[f] = true
+		}
+	}
+	for _, f := range cfg.Fields {
+		fields[f] = true
+	}
+	slow := time.Duration(cfg.SlowThreshold) * time.Millisecond
+
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		for _, prefix := range cfg.ExcludePaths {
+			if strings.HasPrefix(path, prefix) {
+				c.Next()
+				return
+			}
+		}
+
+		start := time.Now()
+		c.Next()
+		latency := time.Since(start)
+		status := c.Writer.Status()
+
+		if status < 400 && (slow == 0 || latency < slow) && cfg.SampleRate < 1 && rand.Float64() >= cfg.SampleRate {
+			return
+		}
+
+		entry := make([]zap.Field, 0, len(fields))
+		add := func(name string, f zap.Field) {
+			if fields[name] {
+				entry = append(entry, f)
+			}
+		}
+		add("request_id", zap.String("request_id", c.GetString("X-Request-ID")))
+		if traceID := tracing.TraceID(c.Request.Context()); traceID != "" {
+			add("trace_id", zap.String("trace_id", traceID))
+		}
+		if userID, ok := c.Get("user_id"); ok {
+			add("user_id", zap.Any("user_id", userID))
+		}
+		add("method", zap.String("method", c.Request.Method))
+		add("path", zap.String("path", path))
+		add("route", zap.String("route", c.FullPath()))
+		if c.Request.URL.RawQuery != "" {
+			add("query", zap.String("query", c.Request.URL.RawQuery))
+		}
+		add("status", zap.Int("status", status))
+		add("bytes", zap.Int("bytes", max(c.Writer.Size(), 0)))
+		add("latency", zap.Float64("latency_ms", float64(latency.Microseconds())/1000))
+		add("client_ip", zap.String("client_ip", c.ClientIP()))
+		add("user_agent", zap.String("user_agent", c.Request.UserAgent()))
+		if referer := c.Request.Referer(); referer != "" {
+			add("referer", zap.String("referer", referer))
+		}
+
+		switch {
+		case status >= 500:
+			log.Error("request", entry...)
+		case status >= 400 || (slow > 0 && latency >= slow):
+			log.Warn("request", entry...)
+		default:
+			log.Info("request", entry...)
+		}
+	}
+}
+
+// Timeout sets a deadline on the request context; a zero timeout sets none.
 // Handlers should check ctx.Err() for long-running operations.
-func Timeout(timeout time.Duration, skipPaths ...string) gin.HandlerFunc {
+func Timeout(timeout func() time.Duration, skipPaths ...string) gin.HandlerFunc {
 	skipMap := make(map[string]bool, len(skipPaths))
 	for _, p := range skipPaths {
 		skipMap[p] = true
@@ -88,7 +220,12 @@ func Timeout(timeout time.Duration, skipPaths ...string) gin.HandlerFunc {
 				return
 			}
 		}
-		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		d := timeout()
+		if d <= 0 {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
 		defer cancel()
 		c.Request = c.Request.WithContext(ctx)
 		c.Next()
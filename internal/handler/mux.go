@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// NewMixedHandler serves gRPC and REST on a single listener.
+// HTTP/2 requests with an application/grpc content type go to grpcServer,
+// everything else to httpHandler. Pass h2s for plaintext listeners so
+// gRPC clients can speak HTTP/2 without TLS (prior knowledge or Upgrade);
+// with TLS, net/http negotiates h2 via ALPN on its own.
+func NewMixedHandler(httpHandler, grpcServer http.Handler, h2s *http2.Server) http.Handler {
+	mixed := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isGRPCRequest(r) {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		httpHandler.ServeHTTP(w, r)
+	})
+
+	if h2s != nil {
+		return h2c.NewHandler(mixed, h2s)
+	}
+	return mixed
+}
+
+// isGRPCRequest reports whether r is a native gRPC call (gRPC-Web is left to the HTTP handler)
+func isGRPCRequest(r *http.Request) bool {
+	if r.ProtoMajor != 2 {
+		return false
+	}
+	ct := r.Header.Get("Content-Type")
+	return ct == "application/grpc" ||
+		strings.HasPrefix(ct, "application/grpc+") ||
+		strings.HasPrefix(ct, "application/grpc;")
+}
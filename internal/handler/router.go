@@ -5,12 +5,16 @@ import (
 	"net/http"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"go-api-scaffold/internal/jobs"
+	"go-api-scaffold/internal/scheduler"
 	"go-api-scaffold/internal/service"
 	"go-api-scaffold/internal/web"
 	"go-api-scaffold/pkg/config"
 	"go-api-scaffold/pkg/logger"
+	"go-api-scaffold/pkg/metrics"
 	"go-api-scaffold/pkg/response"
 
 	_ "go-api-scaffold/docs/swagger"
@@ -20,18 +24,50 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-// NewRouter creates the HTTP router
-func NewRouter(cfg *config.Config, authSvc *service.AuthService, exampleSvc *service.ExampleService) *gin.Engine {
+// NewRouter creates the HTTP router.
+// grpcServer may be nil; it is required for grpc.web and grpc.gateway.
+// backupSvc is nil unless the database is sqlite.
+// reload may be nil; otherwise CORS, rate limits and the request timeout follow config changes.
+func NewRouter(cfg *config.Config, reload *config.Watcher, authSvc *service.AuthService, exampleSvc *service.ExampleService, backupSvc *service.BackupService, jobsMgr *jobs.Manager, sched *scheduler.Scheduler, grpcServer *GRPCServer) *gin.Engine {
 	gin.SetMode(cfg.App.Mode)
 
 	r := gin.New()
+	// Validated with the config; without trusted proxies ClientIP is the peer address,
+	// so clients cannot pick their own rate limit key with X-Forwarded-For
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.Errorf("invalid trusted proxies: %v", err)
+	}
 
 	// Global middleware
 	r.Use(Recovery())
-	r.Use(CORS())
+	if cfg.Tracing.Enabled {
+		r.Use(Tracing())
+	}
+	if cfg.Metrics.Enabled {
+		r.Use(Metrics())
+	}
+	r.Use(CORS(&cfg.CORS, reload))
 	r.Use(RequestID())
-	r.Use(Logger())
-	r.Use(Timeout(30*time.Second, "/ws/", "/health", "/swagger/"))
+	r.Use(requestLogger(&cfg.Log))
+	// Backups and restores take as long as the database is large
+	timeoutSkips := []string{"/ws/", "/health", "/livez", "/readyz", "/swagger/", "/api/v1/admin/backups", cfg.Metrics.Path}
+	var grpcWeb *GRPCWebHandler
+	if cfg.GRPC.Web.Enabled && grpcServer != nil {
+		grpcWeb = NewGRPCWebHandler(grpcServer, cfg.GRPC.Web.Connect)
+		// Streams live as long as the client wants
+		for _, name := range grpcWeb.Services() {
+			timeoutSkips = append(timeoutSkips, "/"+name+"/")
+		}
+	}
+	var requestTimeout atomic.Int64
+	requestTimeout.Store(int64(cfg.Server.RequestTimeout))
+	reload.Subscribe(func(c *config.Config) error {
+		requestTimeout.Store(int64(c.Server.RequestTimeout))
+		return nil
+	}, "server.request_timeout")
+	r.Use(Timeout(func() time.Duration { return time.Duration(requestTimeout.Load()) * time.Second }, timeoutSkips...))
+
+	rl := NewRateLimiter(&cfg.RateLimit, reload)
 
 	// ====== Base routes ======
 	r.GET("/health", func(c *gin.Context) {
@@ -40,6 +76,12 @@ func NewRouter(cfg *config.Config, authSvc *service.AuthService, exampleSvc *ser
 			"version": cfg.App.Version,
 		})
 	})
+	r.GET("/livez", Livez)
+	r.GET("/readyz", Readyz)
+
+	if cfg.Metrics.Enabled {
+		r.GET(cfg.Metrics.Path, gin.WrapH(metrics.Handler()))
+	}
 
 	// ====== API routes ======
 	api := r.Group("/api/v1")
@@ -47,6 +89,7 @@ func NewRouter(cfg *config.Config, authSvc *service.AuthService, exampleSvc *ser
 		// Auth (no token required)
 		authHandler := NewAuthHandler(authSvc)
 		auth := api.Group("/auth")
+		auth.Use(rl.Middleware("auth"))
 		{
 			auth.POST("/login", authHandler.Login)
 			auth.POST("/refresh", authHandler.RefreshToken)
@@ -54,7 +97,7 @@ func NewRouter(cfg *config.Config, authSvc *service.AuthService, exampleSvc *ser
 
 		// Authenticated routes
 		authorized := api.Group("")
-		authorized.Use(AuthMiddleware(authSvc))
+		authorized.Use(AuthMiddleware(authSvc), rl.Middleware("api"))
 		{
 			authorized.GET("/auth/profile", authHandler.GetProfile)
 
@@ -64,20 +107,63 @@ func NewRouter(cfg *config.Config, authSvc *service.AuthService, exampleSvc *ser
 			{
 				examples.GET("", exampleHandler.List)
 				examples.POST("", exampleHandler.Create)
+				examples.POST("/import", exampleHandler.Import)
 				examples.GET("/:id", exampleHandler.Get)
 				examples.PUT("/:id", exampleHandler.Update)
 				examples.DELETE("/:id", exampleHandler.Delete)
 			}
 
-			// Admin-only routes example
-			// admin := authorized.Group("")
-			// admin.Use(RequireRole("admin"))
-			// { ... }
+			// Background jobs (status of the caller's own jobs)
+			jobHandler := NewJobHandler(jobsMgr)
+			authorized.GET("/jobs/:id", jobHandler.Get)
+
+			// Admin-only routes
+			adminHandler := NewAdminHandler()
+			admin := authorized.Group("/admin")
+			admin.Use(RequireRole("admin"))
+			{
+				admin.GET("/log-level", adminHandler.GetLogLevel)
+				admin.PUT("/log-level", adminHandler.SetLogLevel)
+				admin.GET("/health", adminHandler.GetHealth)
+
+				adminJobs := admin.Group("/jobs")
+				adminJobs.GET("", jobHandler.List)
+				adminJobs.GET("/stats", jobHandler.Stats)
+				adminJobs.GET("/:id", jobHandler.AdminGet)
+				adminJobs.POST("/:id/retry", jobHandler.Retry)
+				adminJobs.POST("/:id/cancel", jobHandler.Cancel)
+
+				taskHandler := NewTaskHandler(sched)
+				tasks := admin.Group("/tasks")
+				tasks.GET("", taskHandler.List)
+				tasks.GET("/:name/runs", taskHandler.Runs)
+				tasks.POST("/:name/pause", taskHandler.Pause)
+				tasks.POST("/:name/resume", taskHandler.Resume)
+				tasks.POST("/:name/trigger", taskHandler.Trigger)
+
+				// SQLite snapshots
+				if backupSvc != nil {
+					backupHandler := NewBackupHandler(backupSvc)
+					admin.GET("/backups", backupHandler.List)
+					admin.POST("/backups", backupHandler.Create)
+					admin.POST("/backups/:name/restore", backupHandler.Restore)
+				}
+			}
 
 			// GEN:ROUTE_REGISTER - Auto-appended by code generator, do not remove
 		}
 	}
 
+	// ====== gRPC-Gateway (proto services as JSON/HTTP) ======
+	if cfg.GRPC.Gateway.Enabled {
+		registerGatewayRoutes(r, cfg.GRPC.Gateway.Prefix, authSvc, grpcServer, rl)
+	}
+
+	// ====== gRPC-Web / Connect (browser clients) ======
+	if grpcWeb != nil {
+		registerGRPCWebRoutes(r, grpcWeb, authSvc, rl)
+	}
+
 	// ====== Swagger ======
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -87,6 +173,48 @@ func NewRouter(cfg *config.Config, authSvc *service.AuthService, exampleSvc *ser
 	return r
 }
 
+// requestLogger returns the access log middleware when enabled, else the error/slow request logger
+func requestLogger(cfg *config.LogConfig) gin.HandlerFunc {
+	if !cfg.Access.Enabled {
+		return Logger()
+	}
+	accessLog, err := logger.NewAccess(cfg)
+	if err != nil {
+		logger.Errorf("failed to init access log: %v", err)
+		return Logger()
+	}
+	return AccessLog(accessLog, &cfg.Access)
+}
+
+// registerGatewayRoutes mounts the gateway under prefix, behind JWT authentication
+func registerGatewayRoutes(r *gin.Engine, prefix string, authSvc *service.AuthService, grpcServer *GRPCServer, rl *RateLimiter) {
+	if grpcServer == nil {
+		logger.Errorf("gRPC-Gateway needs the gRPC server")
+		return
+	}
+	gw, err := NewGateway(grpcServer)
+	if err != nil {
+		logger.Errorf("failed to init gRPC-Gateway: %v", err)
+		return
+	}
+
+	prefix = strings.TrimSuffix(prefix, "/")
+	gateway := r.Group(prefix)
+	gateway.Use(AuthMiddleware(authSvc), rl.Middleware("gateway"))
+	gateway.Any("/*path", gin.WrapH(http.StripPrefix(prefix, gw)))
+	logger.Infof("gRPC-Gateway mounted at %s", prefix)
+}
+
+// registerGRPCWebRoutes exposes each gRPC service at /<package.Service>/:method, behind JWT authentication
+func registerGRPCWebRoutes(r *gin.Engine, h *GRPCWebHandler, authSvc *service.AuthService, rl *RateLimiter) {
+	for _, name := range h.Services() {
+		svc := r.Group("/" + name)
+		svc.Use(h.Rejections(), AuthMiddleware(authSvc), rl.Middleware("grpc_web"))
+		svc.POST("/:method", h.Handle)
+		logger.Infof("gRPC-Web enabled: /%s/*", name)
+	}
+}
+
 // registerFrontendRoutes registers frontend static file routes (SPA support)
 func registerFrontendRoutes(r *gin.Engine) {
 	// Method 1: go:embed
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"go-api-scaffold/internal/scheduler"
+	"go-api-scaffold/pkg/logger"
+	"go-api-scaffold/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TaskHandler handles periodic task endpoints (admin role only)
+type TaskHandler struct {
+	sched *scheduler.Scheduler
+}
+
+func NewTaskHandler(s *scheduler.Scheduler) *TaskHandler {
+	return &TaskHandler{sched: s}
+}
+
+// List returns the periodic tasks with their schedule and last run
+// @Summary  List scheduled tasks
+// @Tags     Admin
+// @Security Bearer
+// @Success  200 {object} response.Response{data=[]scheduler.TaskInfo}
+// @Router   /admin/tasks [get]
+func (h *TaskHandler) List(c *gin.Context) {
+	tasks, err := h.sched.List(c.Request.Context())
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Errorw("list tasks failed", "error", err)
+		response.ServerError(c, "query failed")
+		return
+	}
+	response.Success(c, tasks)
+}
+
+// Runs returns the run history of a task
+// @Summary  Task run history
+// @Tags     Admin
+// @Security Bearer
+// @Param    name      path  string true  "Task name"
+// @Param    page      query int    false "Page number" default(1)
+// @Param    page_size query int    false "Page size"   default(10)
+// @Success  200 {object} response.Response{data=response.PageData}
+// @Router   /admin/tasks/{name}/runs [get]
+func (h *TaskHandler) Runs(c *gin.Context) {
+	var page response.PageQuery
+	if err := c.ShouldBindQuery(&page); err != nil {
+		response.ParamError(c, "invalid parameters")
+		return
+	}
+	page.Normalize()
+
+	runs, total, err := h.sched.Runs(c.Request.Context(), c.Param("name"), page.Page, page.PageSize)
+	if errors.Is(err, scheduler.ErrNotFound) {
+		response.NotFound(c, "task not found")
+		return
+	}
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Errorw("list task runs failed", "error", err)
+		response.ServerError(c, "query failed")
+		return
+	}
+	response.SuccessPage(c, runs, total, page.Page, page.PageSize)
+}
+
+// Pause stops the scheduled runs of a task on all instances
+// @Summary  Pause task
+// @Tags     Admin
+// @Security Bearer
+// @Param    name path string true "Task name"
+// @Success  200 {object} response.Response
+// @Router   /admin/tasks/{name}/pause [post]
+func (h *TaskHandler) Pause(c *gin.Context) {
+	h.setPaused(c, "paused", h.sched.Pause)
+}
+
+// Resume restarts the scheduled runs of a paused task
+// @Summary  Resume task
+// @Tags     Admin
+// @Security Bearer
+// @Param    name path string true "Task name"
+// @Success  200 {object} response.Response
+// @Router   /admin/tasks/{name}/resume [post]
+func (h *TaskHandler) Resume(c *gin.Context) {
+	h.setPaused(c, "resumed", h.sched.Resume)
+}
+
+func (h *TaskHandler) setPaused(c *gin.Context, action string, fn func(context.Context, string) error) {
+	name := c.Param("name")
+	if err := fn(c.Request.Context(), name); err != nil {
+		if errors.Is(err, scheduler.ErrNotFound) {
+			response.NotFound(c, "task not found")
+			return
+		}
+		logger.FromContext(c.Request.Context()).Errorw("update task failed", "task", name, "error", err)
+		response.ServerError(c, "update failed")
+		return
+	}
+	logger.FromContext(c.Request.Context()).Infow("task "+action, "task", name)
+	response.OK(c)
+}
+
+// Trigger runs a task now, even when paused; the run is returned while it executes
+// @Summary  Run task now
+// @Tags     Admin
+// @Security Bearer
+// @Param    name path string true "Task name"
+// @Success  200 {object} response.Response{data=model.TaskRun}
+// @Router   /admin/tasks/{name}/trigger [post]
+func (h *TaskHandler) Trigger(c *gin.Context) {
+	name := c.Param("name")
+	run, err := h.sched.Trigger(c.Request.Context(), name)
+	switch {
+	case errors.Is(err, scheduler.ErrNotFound):
+		response.NotFound(c, "task not found")
+	case errors.Is(err, scheduler.ErrRunning):
+		response.Conflict(c, err.Error())
+	case err != nil:
+		logger.FromContext(c.Request.Context()).Errorw("trigger task failed", "task", name, "error", err)
+		response.ServerError(c, "trigger failed")
+	default:
+		logger.FromContext(c.Request.Context()).Infow("task triggered", "task", name, "run", run.ID)
+		response.Success(c, run)
+	}
+}
@@ -0,0 +1,483 @@
+// Package jobs runs background work that must outlive a request (exports,
+// emails, imports). Jobs are stored in the jobs table, so any instance with
+// jobs.enabled can run them and they survive restarts. Modules register a
+// Handler per job type and enqueue jobs with payloads:
+//
+//	m.Handle("mail.send", mailer.Send)
+//	job, err := m.Enqueue(ctx, "mail.send", msg, jobs.OnQueue("mail"), jobs.Delay(time.Minute))
+//
+// Failed jobs are retried with exponential backoff and dead-lettered after
+// their last attempt, or at once when the handler returns Permanent(err).
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-api-scaffold/internal/model"
+	"go-api-scaffold/internal/scheduler"
+	"go-api-scaffold/internal/store"
+	"go-api-scaffold/pkg/config"
+	"go-api-scaffold/pkg/logger"
+	"go-api-scaffold/pkg/metrics"
+	"go-api-scaffold/pkg/tracing"
+
+	"gorm.io/gorm"
+)
+
+// DefaultQueue is the queue of jobs enqueued without OnQueue
+const DefaultQueue = "default"
+
+// maintenanceInterval is how often the jobs of lost workers are requeued
+const maintenanceInterval = time.Minute
+
+var (
+	ErrUnknownType  = errors.New("no handler registered for job type")
+	ErrUnknownQueue = errors.New("queue not configured in jobs.queues")
+	ErrNotFound     = errors.New("job not found")
+	ErrState        = errors.New("job is not in a state that allows this")
+	ErrLost         = errors.New("job no longer held by this run")
+)
+
+// Handler runs a job. The payload is in job.Payload (see Decode). ctx is
+// cancelled at jobs.timeout and when shutdown stops waiting for the job; the
+// job is then run again later.
+type Handler func(ctx context.Context, job *model.Job) error
+
+// Succeed marks job as succeeded in tx, the transaction the handler writes
+// its results in, so that a crash cannot leave the results committed and the
+// job to be run again. It returns ErrLost when the run no longer holds the
+// job (it timed out and was requeued); tx must then be rolled back.
+func Succeed(ctx context.Context, tx *store.Store, job *model.Job) error {
+	ok, err := store.NewJobRepository(tx).Succeed(ctx, job)
+	if err == nil && !ok {
+		err = ErrLost
+	}
+	return err
+}
+
+// Decode unmarshals the payload of a job
+func Decode(job *model.Job, out interface{}) error {
+	if err := json.Unmarshal([]byte(job.Payload), out); err != nil {
+		return Permanent(fmt.Errorf("decode payload: %w", err))
+	}
+	return nil
+}
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error that retrying cannot fix: the job is dead-lettered at once
+func Permanent(err error) error {
+	return permanentError{err}
+}
+
+// Option customizes an enqueued job
+type Option func(*model.Job)
+
+// OnQueue enqueues the job on a queue other than DefaultQueue
+func OnQueue(name string) Option {
+	return func(j *model.Job) { j.Queue = name }
+}
+
+// Delay runs the job no earlier than d from now
+func Delay(d time.Duration) Option {
+	return func(j *model.Job) { j.RunAt = time.Now().Add(d) }
+}
+
+// At runs the job no earlier than t
+func At(t time.Time) Option {
+	return func(j *model.Job) { j.RunAt = t }
+}
+
+// MaxAttempts overrides jobs.max_attempts
+func MaxAttempts(n int) Option {
+	return func(j *model.Job) { j.MaxAttempts = n }
+}
+
+// CreatedBy records the user who enqueued the job (the job status endpoint shows it to them)
+func CreatedBy(userID uint) Option {
+	return func(j *model.Job) { j.CreatedBy = userID }
+}
+
+// Manager enqueues jobs and runs the workers of the configured queues
+type Manager struct {
+	repo   *store.JobRepository
+	cfg    config.JobsConfig
+	worker string // identifies this instance in jobs.locked_by
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	wake     map[string]chan struct{} // per queue: a job was enqueued or a worker freed
+
+	stop    chan struct{}      // closed by Stop: claim no more jobs
+	ctx     context.Context    // parent of the job contexts
+	abandon context.CancelFunc // cancels running jobs when draining times out
+	pollers sync.WaitGroup
+	running sync.WaitGroup
+}
+
+// New creates a job manager; workers start with Start
+func New(db *store.Store, cfg *config.JobsConfig) *Manager {
+	host, _ := os.Hostname()
+	m := &Manager{
+		repo:     store.NewJobRepository(db),
+		cfg:      *cfg,
+		worker:   fmt.Sprintf("%s-%d", host, os.Getpid()),
+		handlers: make(map[string]Handler),
+		wake:     make(map[string]chan struct{}, len(cfg.Queues)),
+	}
+	for queue := range cfg.Queues {
+		m.wake[queue] = make(chan struct{}, 1)
+	}
+	return m
+}
+
+// Handle registers the handler of a job type
+func (m *Manager) Handle(typ string, h Handler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.handlers[typ]; ok {
+		panic("jobs: duplicate handler for " + typ)
+	}
+	m.handlers[typ] = h
+}
+
+// Types returns the registered job types
+func (m *Manager) Types() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	types := make([]string, 0, len(m.handlers))
+	for typ := range m.handlers {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func (m *Manager) handler(typ string) Handler {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.handlers[typ]
+}
+
+// Enqueue stores a job; payload is marshalled to JSON
+func (m *Manager) Enqueue(ctx context.Context, typ string, payload interface{}, opts ...Option) (*model.Job, error) {
+	if m.handler(typ) == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, typ)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode payload: %w", err)
+	}
+
+	job := &model.Job{
+		Queue:       DefaultQueue,
+		Type:        typ,
+		Payload:     string(data),
+		Status:      model.JobPending,
+		RunAt:       time.Now(),
+		MaxAttempts: m.cfg.MaxAttempts,
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+	if _, ok := m.cfg.Queues[job.Queue]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownQueue, job.Queue)
+	}
+	if job.MaxAttempts < 1 {
+		job.MaxAttempts = 1
+	}
+
+	if err := m.repo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	if !job.RunAt.After(time.Now()) {
+		m.notify(job.Queue)
+	}
+	return job, nil
+}
+
+// Get returns a job by ID
+func (m *Manager) Get(ctx context.Context, id uint) (*model.Job, error) {
+	job, err := m.repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return job, err
+}
+
+// List returns a paginated list of jobs, newest first
+func (m *Manager) List(ctx context.Context, req *model.QueryJobRequest) ([]model.Job, int64, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+	return m.repo.List(ctx, req.Page, req.PageSize, req.Queue, req.Type, req.Status)
+}
+
+// Stats counts the jobs by queue and status
+func (m *Manager) Stats(ctx context.Context) ([]model.JobStats, error) {
+	return m.repo.Stats(ctx)
+}
+
+// Retry requeues a dead or cancelled job with fresh attempts
+func (m *Manager) Retry(ctx context.Context, id uint) (*model.Job, error) {
+	return m.transition(ctx, id, m.repo.Requeue)
+}
+
+// Cancel cancels a pending job
+func (m *Manager) Cancel(ctx context.Context, id uint) (*model.Job, error) {
+	return m.transition(ctx, id, m.repo.Cancel)
+}
+
+func (m *Manager) transition(ctx context.Context, id uint, update func(context.Context, uint) (bool, error)) (*model.Job, error) {
+	job, err := m.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := update(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w (status %s)", ErrState, job.Status)
+	}
+	job, err = m.Get(ctx, id)
+	if err == nil && job.Status == model.JobPending {
+		m.notify(job.Queue)
+	}
+	return job, err
+}
+
+// Start recovers the jobs of crashed workers and starts the workers
+func (m *Manager) Start(context.Context) error {
+	if !m.cfg.Enabled {
+		logger.Info("job workers disabled in this instance (jobs.enabled)")
+		return nil
+	}
+
+	m.stop = make(chan struct{})
+	m.ctx, m.abandon = context.WithCancel(context.Background())
+	m.maintain()
+
+	m.pollers.Add(1)
+	go func() {
+		defer m.pollers.Done()
+		t := time.NewTicker(maintenanceInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-m.stop:
+				return
+			case <-t.C:
+				m.maintain()
+			}
+		}
+	}()
+
+	for queue, workers := range m.cfg.Queues {
+		m.pollers.Add(1)
+		go m.poll(queue, workers)
+		logger.Infof("job queue %q started with %d workers", queue, workers)
+	}
+	return nil
+}
+
+// Stop claims no more jobs and waits for the running ones until ctx expires.
+// Jobs still running then are cancelled and returned to the queue.
+func (m *Manager) Stop(ctx context.Context) error {
+	if m.stop == nil {
+		return nil
+	}
+	close(m.stop)
+	m.pollers.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		m.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		m.abandon()
+		return nil
+	case <-ctx.Done():
+		logger.Warn("jobs still running at the drain timeout, returning them to the queue")
+		m.abandon()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+		}
+		return ctx.Err()
+	}
+}
+
+// poll claims due jobs of a queue whenever a worker is free
+func (m *Manager) poll(queue string, workers int) {
+	defer m.pollers.Done()
+
+	var busy atomic.Int32
+	interval := time.Duration(m.cfg.PollInterval) * time.Millisecond
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		if free := workers - int(busy.Load()); free > 0 {
+			claimed, err := m.repo.Claim(context.Background(), queue, m.worker, time.Now(), free)
+			if err != nil {
+				logger.Errorf("job queue %q: claim: %v", queue, err)
+			}
+			for i := range claimed {
+				job := claimed[i]
+				busy.Add(1)
+				m.running.Add(1)
+				go func() {
+					defer m.running.Done()
+					m.run(&job)
+					busy.Add(-1)
+					m.notify(queue)
+				}()
+			}
+			// A full batch means more jobs may be due
+			if err == nil && len(claimed) == free {
+				continue
+			}
+		}
+
+		timer.Reset(interval)
+		select {
+		case <-m.stop:
+			return
+		case <-m.wake[queue]:
+		case <-timer.C:
+		}
+	}
+}
+
+// run executes a claimed job and records the outcome
+func (m *Manager) run(job *model.Job) {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(m.ctx, time.Duration(m.cfg.Timeout)*time.Second)
+	defer cancel()
+
+	err := m.call(ctx, job)
+
+	// The outcome is saved even when the job context has expired
+	saveCtx, saveCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer saveCancel()
+
+	var outcome string
+	var permanent permanentError
+	switch {
+	case err == nil:
+		outcome = "succeeded"
+		_, err = m.repo.Succeed(saveCtx, job) // false when the handler called Succeed
+	case m.ctx.Err() != nil:
+		outcome = "released"
+		logger.Warnf("job %d (%s) interrupted by shutdown, returned to the queue", job.ID, job.Type)
+		err = m.repo.Release(saveCtx, job)
+	case errors.As(err, &permanent) || job.Attempts >= job.MaxAttempts:
+		outcome = "dead"
+		logger.Errorf("job %d (%s) failed on attempt %d/%d, dead-lettered: %v", job.ID, job.Type, job.Attempts, job.MaxAttempts, err)
+		err = m.repo.Bury(saveCtx, job, err.Error())
+	default:
+		outcome = "retried"
+		delay := m.backoff(job.Attempts)
+		logger.Warnf("job %d (%s) failed on attempt %d/%d, retrying in %v: %v", job.ID, job.Type, job.Attempts, job.MaxAttempts, delay, err)
+		err = m.repo.Retry(saveCtx, job, time.Now().Add(delay), err.Error())
+	}
+	if err != nil {
+		logger.Errorf("job %d (%s): save outcome %s: %v", job.ID, job.Type, outcome, err)
+	}
+	metrics.ObserveJob(job.Queue, job.Type, outcome, time.Since(start))
+}
+
+// call runs the handler, turning a panic into an error
+func (m *Manager) call(ctx context.Context, job *model.Job) (err error) {
+	ctx, span := tracing.Start(ctx, "job "+job.Type)
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+		tracing.End(span, err)
+	}()
+
+	h := m.handler(job.Type)
+	if h == nil {
+		// Enqueued by an instance running a newer version
+		return fmt.Errorf("%w: %s", ErrUnknownType, job.Type)
+	}
+	return h(ctx, job)
+}
+
+// backoff returns the delay before the retry after attempt n: jobs.backoff
+// doubled per attempt up to jobs.max_backoff, with 20% jitter
+func (m *Manager) backoff(n int) time.Duration {
+	d := time.Duration(m.cfg.Backoff) * time.Second
+	limit := time.Duration(m.cfg.MaxBackoff) * time.Second
+	for i := 1; i < n && d < limit; i++ {
+		d *= 2
+	}
+	d = min(d, limit)
+	return d - time.Duration(rand.Float64()*0.2*float64(d))
+}
+
+// maintain requeues the jobs of crashed workers
+func (m *Manager) maintain() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// A job running longer than the timeout was abandoned by its worker
+	lease := time.Duration(m.cfg.Timeout)*time.Second + maintenanceInterval
+	if n, err := m.repo.RequeueStale(ctx, time.Now().Add(-lease)); err != nil {
+		logger.Errorf("requeue stale jobs: %v", err)
+	} else if n > 0 {
+		logger.Warnf("recovered %d jobs of lost workers", n)
+		for queue := range m.cfg.Queues {
+			m.notify(queue)
+		}
+	}
+}
+
+// RegisterTasks registers the periodic tasks of the job queue
+func (m *Manager) RegisterTasks(s *scheduler.Scheduler) {
+	if m.cfg.Retention > 0 {
+		s.Register("purge_jobs", "@hourly", m.purge)
+	}
+}
+
+// purge deletes succeeded and cancelled jobs older than jobs.retention
+func (m *Manager) purge(ctx context.Context) error {
+	before := time.Now().Add(-time.Duration(m.cfg.Retention) * time.Hour)
+	n, err := m.repo.Purge(ctx, before)
+	if err == nil && n > 0 {
+		logger.Infof("purged %d finished jobs", n)
+	}
+	return err
+}
+
+// notify wakes the poller of a queue served by this instance
+func (m *Manager) notify(queue string) {
+	if ch, ok := m.wake[queue]; ok {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
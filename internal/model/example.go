@@ -25,6 +25,11 @@ type CreateExampleRequest struct {
 	Status      string `json:"status" binding:"omitempty,oneof=active inactive"`
 }
 
+// ImportExampleRequest creates examples in a background job
+type ImportExampleRequest struct {
+	Items []CreateExampleRequest `json:"items" binding:"required,min=1,max=10000,dive"`
+}
+
 // UpdateExampleRequest is the update request
 type UpdateExampleRequest struct {
 	Name        *string `json:"name" binding:"omitempty,max=100"`
@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// Job statuses
+const (
+	JobPending   = "pending"   // waiting for run_at (new or retrying)
+	JobRunning   = "running"   // claimed by a worker
+	JobSucceeded = "succeeded" // finished
+	JobDead      = "dead"      // attempts exhausted or permanent error (dead letter)
+	JobCancelled = "cancelled" // cancelled before it ran
+)
+
+// Job is a background job (see internal/jobs)
+type Job struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	Queue       string     `json:"queue" gorm:"size:50;not null;index:idx_jobs_poll,priority:1"`
+	Type        string     `json:"type" gorm:"size:100;not null;index"`
+	Payload     string     `json:"payload" gorm:"type:text"` // JSON
+	Status      string     `json:"status" gorm:"size:20;not null;index:idx_jobs_poll,priority:2"`
+	RunAt       time.Time  `json:"run_at" gorm:"not null;index:idx_jobs_poll,priority:3"` // not before
+	Attempts    int        `json:"attempts"`                                              // runs started, including the current one
+	MaxAttempts int        `json:"max_attempts"`
+	LastError   string     `json:"last_error" gorm:"type:text"`
+	LockedBy    string     `json:"locked_by" gorm:"size:100"` // worker instance while running
+	LockedAt    *time.Time `json:"locked_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	CreatedBy   uint       `json:"created_by" gorm:"index"` // user who enqueued the job, 0 for the system
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName overrides the table name
+func (Job) TableName() string {
+	return "jobs"
+}
+
+// QueryJobRequest is the job list query
+type QueryJobRequest struct {
+	Page     int    `form:"page" json:"page"`
+	PageSize int    `form:"page_size" json:"page_size"`
+	Queue    string `form:"queue" json:"queue"`
+	Type     string `form:"type" json:"type"`
+	Status   string `form:"status" json:"status" binding:"omitempty,oneof=pending running succeeded dead cancelled"`
+}
+
+// JobStats counts the jobs of a queue by status
+type JobStats struct {
+	Queue  string         `json:"queue"`
+	Counts map[string]int `json:"counts"`
+}
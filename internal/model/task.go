@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// Task run statuses
+const (
+	TaskRunning   = "running"
+	TaskSucceeded = "succeeded"
+	TaskFailed    = "failed"
+)
+
+// Task run triggers
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+// ScheduledTask is the shared state of a periodic task (see internal/scheduler):
+// the pause flag and the lock that lets one replica run it
+type ScheduledTask struct {
+	Name        string     `json:"name" gorm:"primaryKey;size:100"`
+	Paused      bool       `json:"paused" gorm:"not null;default:false"`
+	LockedBy    string     `json:"locked_by" gorm:"size:100"` // instance running it
+	LockedUntil *time.Time `json:"locked_until"`              // lock expiry, so a crashed instance cannot hold it
+	LastSlot    *time.Time `json:"last_slot"`                 // last scheduled time claimed, each runs once
+	LastRunAt   *time.Time `json:"last_run_at"`
+	LastStatus  string     `json:"last_status" gorm:"size:20"`
+	LastError   string     `json:"last_error" gorm:"type:text"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName overrides the table name
+func (ScheduledTask) TableName() string {
+	return "scheduled_tasks"
+}
+
+// TaskRun is one execution of a periodic task
+type TaskRun struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	Task       string     `json:"task" gorm:"size:100;not null;index"`
+	Trigger    string     `json:"trigger" gorm:"size:20;not null"` // schedule, manual
+	Instance   string     `json:"instance" gorm:"size:100"`
+	Status     string     `json:"status" gorm:"size:20;not null"`
+	Error      string     `json:"error" gorm:"type:text"`
+	StartedAt  time.Time  `json:"started_at" gorm:"index"`
+	FinishedAt *time.Time `json:"finished_at"`
+	DurationMs int64      `json:"duration_ms"`
+}
+
+// TableName overrides the table name
+func (TaskRun) TableName() string {
+	return "task_runs"
+}
@@ -2,6 +2,12 @@ package model
 
 import "time"
 
+// User roles (see handler.RequireRole)
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
 // User is the user model (JWT authentication)
 type User struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
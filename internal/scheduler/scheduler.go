@@ -0,0 +1,415 @@
+// Package scheduler runs periodic tasks on cron schedules or fixed intervals.
+// Modules register their tasks at startup:
+//
+//	s.Register("purge_tokens", "0 3 * * *", tokens.PurgeExpired)
+//	s.Register("refresh_stats", "@every 10m", stats.Refresh)
+//
+// Every replica runs the scheduler, but each scheduled time of a task is
+// claimed in the scheduled_tasks table, so only one replica runs it. Runs are
+// recorded in task_runs; tasks can be paused and triggered by admins.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"go-api-scaffold/internal/model"
+	"go-api-scaffold/internal/store"
+	"go-api-scaffold/pkg/config"
+	"go-api-scaffold/pkg/cron"
+	"go-api-scaffold/pkg/logger"
+	"go-api-scaffold/pkg/tracing"
+)
+
+// lockMargin keeps a task locked a little longer than its timeout, while the
+// run outcome is saved
+const lockMargin = time.Minute
+
+var (
+	ErrNotFound = errors.New("task not found")
+	ErrRunning  = errors.New("task is already running")
+)
+
+// Task names are config keys (scheduler.schedules), so they cannot contain dots
+var validName = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// Func is the work of a task. ctx is cancelled at scheduler.timeout and when
+// shutdown stops waiting for the run.
+type Func func(ctx context.Context) error
+
+type task struct {
+	name     string
+	spec     string        // effective schedule, "off" when disabled
+	schedule cron.Schedule // nil when disabled
+	fn       Func
+	next     time.Time
+}
+
+// TaskInfo is the state of a task across replicas
+type TaskInfo struct {
+	Name       string     `json:"name"`
+	Schedule   string     `json:"schedule"`
+	Paused     bool       `json:"paused"`
+	Running    bool       `json:"running"`
+	RunningOn  string     `json:"running_on,omitempty"`
+	NextRunAt  *time.Time `json:"next_run_at"` // nil when paused or disabled
+	LastRunAt  *time.Time `json:"last_run_at"`
+	LastStatus string     `json:"last_status"`
+	LastError  string     `json:"last_error"`
+}
+
+// Scheduler runs the registered tasks
+type Scheduler struct {
+	repo     *store.TaskRepository
+	cfg      config.SchedulerConfig
+	loc      *time.Location
+	instance string // identifies this instance in the task locks
+
+	mu    sync.Mutex
+	tasks map[string]*task
+
+	stop    chan struct{}      // closed by Stop
+	done    chan struct{}      // closed when the loop has returned
+	ctx     context.Context    // parent of the run contexts
+	abandon context.CancelFunc // cancels running tasks when draining times out
+	running sync.WaitGroup
+}
+
+// New creates a scheduler with the built-in history cleanup task
+func New(db *store.Store, cfg *config.SchedulerConfig) *Scheduler {
+	host, _ := os.Hostname()
+	// time.LoadLocation("") is UTC; an empty timezone means local time
+	loc := time.Local
+	if cfg.Timezone != "" {
+		if l, err := time.LoadLocation(cfg.Timezone); err == nil {
+			loc = l // otherwise rejected by config validation
+		}
+	}
+	s := &Scheduler{
+		repo:     store.NewTaskRepository(db),
+		cfg:      *cfg,
+		loc:      loc,
+		instance: fmt.Sprintf("%s-%d", host, os.Getpid()),
+		tasks:    make(map[string]*task),
+	}
+	s.ctx, s.abandon = context.WithCancel(context.Background())
+
+	if cfg.Retention > 0 {
+		s.Register("purge_task_runs", "@hourly", s.purgeRuns)
+	}
+	return s
+}
+
+// Register adds a task. spec is a cron expression, a descriptor such as
+// @daily or an interval such as "@every 5m" (see pkg/cron); an entry in
+// scheduler.schedules overrides it. Register panics on an invalid name or
+// spec, or a duplicate name, like an invalid route does.
+func (s *Scheduler) Register(name, spec string, fn Func) {
+	if !validName.MatchString(name) {
+		panic(fmt.Sprintf("scheduler: invalid task name %q (lowercase letters, digits, _ and -)", name))
+	}
+	if override, ok := s.cfg.Schedules[name]; ok {
+		spec = override
+	}
+	t := &task{name: name, spec: spec, fn: fn}
+	if spec != "off" {
+		schedule, err := cron.Parse(spec)
+		if err != nil {
+			panic(fmt.Sprintf("scheduler: task %s: %v", name, err))
+		}
+		t.schedule = schedule
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[name]; ok {
+		panic("scheduler: duplicate task " + name)
+	}
+	s.tasks[name] = t
+}
+
+// Start creates the state of new tasks and, when enabled, starts the schedule
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	names := make([]string, 0, len(s.tasks))
+	for name := range s.tasks {
+		names = append(names, name)
+	}
+	s.mu.Unlock()
+	if err := s.repo.Ensure(ctx, names); err != nil {
+		return fmt.Errorf("register tasks: %w", err)
+	}
+
+	lost := time.Duration(s.cfg.Timeout)*time.Second + lockMargin
+	if n, err := s.repo.FailLostRuns(ctx, time.Now().Add(-lost)); err != nil {
+		logger.Errorf("scheduler: %v", err)
+	} else if n > 0 {
+		logger.Warnf("scheduler: marked %d runs of lost instances as failed", n)
+	}
+
+	if !s.cfg.Enabled {
+		logger.Info("scheduler disabled in this instance (scheduler.enabled)")
+		return nil
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.loop()
+	logger.Infof("scheduler started with %d tasks", len(names))
+	return nil
+}
+
+// Stop ends the schedule and waits for running tasks until ctx expires; they
+// are cancelled then
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s.stop != nil {
+		close(s.stop)
+		<-s.done
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		s.abandon()
+		return nil
+	case <-ctx.Done():
+		logger.Warn("scheduled tasks still running at the drain timeout, cancelling them")
+		s.abandon()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+		}
+		return ctx.Err()
+	}
+}
+
+// loop starts each task at its scheduled times
+func (s *Scheduler) loop() {
+	defer close(s.done)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-timer.C:
+		}
+
+		now := time.Now().In(s.loc)
+		wake := now.Add(time.Minute) // also picks up clock changes
+		s.mu.Lock()
+		for _, t := range s.tasks {
+			if t.schedule == nil {
+				continue
+			}
+			if t.next.IsZero() {
+				if t.next = t.schedule.Next(now); t.next.IsZero() {
+					continue // no activation within five years
+				}
+			}
+			if !t.next.After(now) {
+				slot := t.next
+				t.next = t.schedule.Next(now)
+				s.running.Add(1)
+				go func(t *task) {
+					defer s.running.Done()
+					s.runScheduled(t, slot)
+				}(t)
+			}
+			if !t.next.IsZero() && t.next.Before(wake) {
+				wake = t.next
+			}
+		}
+		s.mu.Unlock()
+		timer.Reset(time.Until(wake))
+	}
+}
+
+// runScheduled runs a task for a scheduled time unless another replica claimed
+// it, the task is paused or still running
+func (s *Scheduler) runScheduled(t *task, slot time.Time) {
+	run, err := s.acquire(context.Background(), t, &slot, model.TriggerSchedule)
+	if err != nil {
+		if !errors.Is(err, ErrRunning) {
+			logger.Errorf("scheduler: task %s: %v", t.name, err)
+		}
+		return
+	}
+	s.execute(t, run)
+}
+
+// acquire locks the task and records the start of a run
+func (s *Scheduler) acquire(ctx context.Context, t *task, slot *time.Time, trigger string) (*model.TaskRun, error) {
+	now := time.Now()
+	until := now.Add(time.Duration(s.cfg.Timeout)*time.Second + lockMargin)
+	ok, err := s.repo.Acquire(ctx, t.name, s.instance, slot, until)
+	if err != nil {
+		return nil, fmt.Errorf("lock: %w", err)
+	}
+	if !ok {
+		return nil, ErrRunning
+	}
+
+	run := &model.TaskRun{
+		Task:      t.name,
+		Trigger:   trigger,
+		Instance:  s.instance,
+		Status:    model.TaskRunning,
+		StartedAt: now,
+	}
+	if err := s.repo.CreateRun(ctx, run); err != nil {
+		s.release(t, run)
+		return nil, fmt.Errorf("record run: %w", err)
+	}
+	return run, nil
+}
+
+// execute runs a locked task and records the outcome
+func (s *Scheduler) execute(t *task, run *model.TaskRun) {
+	ctx, cancel := context.WithTimeout(s.ctx, time.Duration(s.cfg.Timeout)*time.Second)
+	defer cancel()
+
+	err := s.call(ctx, t)
+	finished := time.Now().UTC()
+	run.FinishedAt = &finished
+	run.DurationMs = finished.Sub(run.StartedAt).Milliseconds()
+	run.Status = model.TaskSucceeded
+	if err != nil {
+		run.Status = model.TaskFailed
+		run.Error = err.Error()
+		logger.Errorf("scheduler: task %s failed after %dms: %v", t.name, run.DurationMs, err)
+	} else {
+		logger.Infof("scheduler: task %s succeeded in %dms", t.name, run.DurationMs)
+	}
+	s.release(t, run)
+}
+
+// release saves the run outcome and unlocks the task
+func (s *Scheduler) release(t *task, run *model.TaskRun) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if run.ID != 0 {
+		if err := s.repo.FinishRun(ctx, run); err != nil {
+			logger.Errorf("scheduler: task %s: save run: %v", t.name, err)
+		}
+	}
+	if err := s.repo.Release(ctx, t.name, s.instance, run); err != nil {
+		logger.Errorf("scheduler: task %s: unlock: %v", t.name, err)
+	}
+}
+
+// call runs the task, turning a panic into an error
+func (s *Scheduler) call(ctx context.Context, t *task) (err error) {
+	ctx, span := tracing.Start(ctx, "task "+t.name)
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+		tracing.End(span, err)
+	}()
+	return t.fn(ctx)
+}
+
+// List returns the registered tasks with their shared state
+func (s *Scheduler) List(ctx context.Context) ([]TaskInfo, error) {
+	states, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]TaskInfo, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		st := states[t.name]
+		info := TaskInfo{
+			Name:       t.name,
+			Schedule:   t.spec,
+			Paused:     st.Paused,
+			Running:    st.LockedUntil != nil && st.LockedUntil.After(now),
+			LastRunAt:  st.LastRunAt,
+			LastStatus: st.LastStatus,
+			LastError:  st.LastError,
+		}
+		if info.Running {
+			info.RunningOn = st.LockedBy
+		}
+		if t.schedule != nil && !st.Paused {
+			next := t.schedule.Next(now.In(s.loc))
+			info.NextRunAt = &next
+		}
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// Pause stops the scheduled runs of a task on all replicas; Trigger still runs it
+func (s *Scheduler) Pause(ctx context.Context, name string) error {
+	return s.setPaused(ctx, name, true)
+}
+
+// Resume restarts the scheduled runs of a paused task
+func (s *Scheduler) Resume(ctx context.Context, name string) error {
+	return s.setPaused(ctx, name, false)
+}
+
+func (s *Scheduler) setPaused(ctx context.Context, name string, paused bool) error {
+	if s.task(name) == nil {
+		return ErrNotFound
+	}
+	return s.repo.SetPaused(ctx, name, paused)
+}
+
+// Trigger runs a task now on this instance, unless it is running anywhere
+func (s *Scheduler) Trigger(ctx context.Context, name string) (*model.TaskRun, error) {
+	t := s.task(name)
+	if t == nil {
+		return nil, ErrNotFound
+	}
+	run, err := s.acquire(ctx, t, nil, model.TriggerManual)
+	if err != nil {
+		return nil, err
+	}
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.execute(t, run)
+	}()
+	return run, nil
+}
+
+// Runs returns the run history of a task, newest first
+func (s *Scheduler) Runs(ctx context.Context, name string, page, pageSize int) ([]model.TaskRun, int64, error) {
+	if s.task(name) == nil {
+		return nil, 0, ErrNotFound
+	}
+	return s.repo.ListRuns(ctx, name, page, pageSize)
+}
+
+func (s *Scheduler) task(name string) *task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tasks[name]
+}
+
+// purgeRuns deletes the run history older than scheduler.retention
+func (s *Scheduler) purgeRuns(ctx context.Context) error {
+	before := time.Now().Add(-time.Duration(s.cfg.Retention) * time.Hour)
+	n, err := s.repo.PurgeRuns(ctx, before)
+	if err == nil && n > 0 {
+		logger.Infof("scheduler: purged %d task runs", n)
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+package seed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fixtures holds the records of a seed set, keyed by seeder name
+type Fixtures map[string]interface{}
+
+// LoadFixtures reads the seed set <dir>/<set>.yaml (.yml or .json)
+func LoadFixtures(dir, set string) (Fixtures, error) {
+	for _, ext := range []string{".yaml", ".yml", ".json"} {
+		path := filepath.Join(dir, set+ext)
+		b, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		// JSON is valid YAML
+		fx := make(Fixtures)
+		if err := yaml.Unmarshal(b, &fx); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		return fx, nil
+	}
+	return nil, fmt.Errorf("seed set %q not found in %s", set, dir)
+}
+
+// Decode decodes the records of a seeder into out (a pointer to a slice)
+// using its JSON field names. A missing section leaves out unchanged.
+func (f Fixtures) Decode(name string, out interface{}) error {
+	section, ok := f[name]
+	if !ok {
+		return nil
+	}
+	b, err := json.Marshal(section)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("fixtures %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// Table returns a seeder that creates the fixture records of model T from
+// the section name, skipping records whose key column (e.g. "name") matches
+// an existing row
+func Table[T any](name, key string) Seeder {
+	return Seeder{Name: name, Run: func(ctx context.Context, env *Env) (Result, error) {
+		var res Result
+		var items []T
+		if err := env.Fixtures.Decode(name, &items); err != nil {
+			return res, err
+		}
+
+		db := env.DB.DB().WithContext(ctx)
+		for i := range items {
+			value, err := fieldValue(db, &items[i], key)
+			if err != nil {
+				return res, err
+			}
+			var count int64
+			if err := db.Model(new(T)).Where(fmt.Sprintf("%s = ?", key), value).Count(&count).Error; err != nil {
+				return res, err
+			}
+			if count > 0 {
+				res.Existing++
+				continue
+			}
+			if err := db.Create(&items[i]).Error; err != nil {
+				return res, fmt.Errorf("create %s %v: %w", key, value, err)
+			}
+			res.Created++
+		}
+		return res, nil
+	}}
+}
+
+// fieldValue returns the value of the field mapped to column in a model
+func fieldValue(db *gorm.DB, item interface{}, column string) (interface{}, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(item); err != nil {
+		return nil, err
+	}
+	field := stmt.Schema.LookUpField(column)
+	if field == nil {
+		return nil, fmt.Errorf("%s has no column %q", stmt.Schema.Name, column)
+	}
+	value, _ := field.ValueOf(db.Statement.Context, reflect.ValueOf(item).Elem())
+	return value, nil
+}
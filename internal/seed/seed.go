@@ -0,0 +1,80 @@
+// Package seed fills the database with the records a deployment or a test
+// needs. Seeders are named per module, run in registration order and are
+// idempotent: records that already exist are left alone.
+package seed
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go-api-scaffold/internal/model"
+	"go-api-scaffold/internal/store"
+	"go-api-scaffold/pkg/config"
+)
+
+// Seeder inserts the records of one module
+type Seeder struct {
+	Name string
+	Run  func(ctx context.Context, env *Env) (Result, error)
+}
+
+// Env is what seeders work with
+type Env struct {
+	DB       *store.Store
+	Admin    config.AdminConfig // account created by the admin seeder
+	Fixtures Fixtures           // records of the seed set, keyed by seeder name
+}
+
+// Result counts the records of one seeder
+type Result struct {
+	Created  int
+	Existing int
+}
+
+// Report is the result of one seeder run
+type Report struct {
+	Name string
+	Result
+}
+
+// Seeders returns the registered seeders in run order. A seeder reads the
+// fixture section with its name.
+func Seeders() []Seeder {
+	return []Seeder{
+		{Name: "admin", Run: seedAdmin},
+		{Name: "users", Run: seedUsers},
+		Table[model.Example]("examples", "name"),
+		// GEN:SEED_REGISTER - Auto-appended by code generator, do not remove
+	}
+}
+
+// Run runs the named seeders, or all of them when names is empty
+func Run(ctx context.Context, env *Env, names ...string) ([]Report, error) {
+	seeders := Seeders()
+	want := make(map[string]bool, len(names))
+	for _, name := range names {
+		want[name] = true
+	}
+	for _, s := range seeders {
+		delete(want, s.Name)
+	}
+	for _, name := range names {
+		if want[name] {
+			return nil, fmt.Errorf("unknown seeder %q", name)
+		}
+	}
+
+	var reports []Report
+	for _, s := range seeders {
+		if len(names) > 0 && !slices.Contains(names, s.Name) {
+			continue
+		}
+		res, err := s.Run(ctx, env)
+		if err != nil {
+			return reports, fmt.Errorf("seed %s: %w", s.Name, err)
+		}
+		reports = append(reports, Report{Name: s.Name, Result: res})
+	}
+	return reports, nil
+}
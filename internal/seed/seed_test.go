@@ -0,0 +1,124 @@
+package seed
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"go-api-scaffold/internal/model"
+	"go-api-scaffold/internal/store"
+	"go-api-scaffold/pkg/config"
+)
+
+func newTestStore(t *testing.T) *store.Store {
+	t.Helper()
+	db, err := store.New(&config.DatabaseConfig{Type: "sqlite", Path: filepath.Join(t.TempDir(), "seed.db"), AutoMigrate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(db.Close)
+	return db
+}
+
+// results returns the result of each seeder by name
+func results(reports []Report) map[string]Result {
+	m := make(map[string]Result, len(reports))
+	for _, r := range reports {
+		m[r.Name] = r.Result
+	}
+	return m
+}
+
+func TestRunIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	db := newTestStore(t)
+	fx, err := LoadFixtures("../../configs/seeds", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := &Env{DB: db, Admin: config.AdminConfig{Username: "admin", Password: "admin-pass"}, Fixtures: fx}
+
+	first, err := Run(ctx, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]Result{
+		"admin":    {Created: 1},
+		"users":    {Created: 2},
+		"examples": {Created: 2},
+	} {
+		if got := results(first)[name]; got != want {
+			t.Errorf("first run %s: got %+v, want %+v", name, got, want)
+		}
+	}
+
+	second, err := Run(ctx, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]Result{
+		"admin":    {Existing: 1},
+		"users":    {Existing: 2},
+		"examples": {Existing: 2},
+	} {
+		if got := results(second)[name]; got != want {
+			t.Errorf("second run %s: got %+v, want %+v", name, got, want)
+		}
+	}
+
+	var users, examples int64
+	db.DB().Model(&model.User{}).Count(&users)
+	db.DB().Model(&model.Example{}).Count(&examples)
+	if users != 3 || examples != 2 {
+		t.Errorf("got %d users and %d examples, want 3 and 2", users, examples)
+	}
+
+	var ex model.Example
+	if err := db.DB().Where("name = ?", "test-inactive").First(&ex).Error; err != nil {
+		t.Fatal(err)
+	}
+	if ex.Description != "fixture" || ex.Status != "inactive" {
+		t.Errorf("fixture fields not stored: %+v", ex)
+	}
+}
+
+func TestRunSelectedSeeders(t *testing.T) {
+	ctx := context.Background()
+	fx, err := LoadFixtures("../../configs/seeds", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := &Env{DB: newTestStore(t), Fixtures: fx}
+
+	reports, err := Run(ctx, env, "examples")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || reports[0].Name != "examples" || reports[0].Created != 2 {
+		t.Errorf("got %+v, want only examples with 2 created", reports)
+	}
+
+	if _, err := Run(ctx, env, "examples", "nope"); err == nil {
+		t.Error("unknown seeder accepted")
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	for _, set := range []string{"dev", "test"} {
+		fx, err := LoadFixtures("../../configs/seeds", set)
+		if err != nil {
+			t.Fatalf("%s: %v", set, err)
+		}
+		var users []userFixture
+		if err := fx.Decode("users", &users); err != nil {
+			t.Fatalf("%s: %v", set, err)
+		}
+		if len(users) == 0 || users[0].Username == "" {
+			t.Errorf("%s: no users decoded: %+v", set, users)
+		}
+	}
+
+	if _, err := LoadFixtures("../../configs/seeds", "missing"); err == nil {
+		t.Error("missing set accepted")
+	}
+}
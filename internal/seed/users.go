@@ -0,0 +1,64 @@
+package seed
+
+import (
+	"context"
+	"errors"
+
+	"go-api-scaffold/internal/model"
+	"go-api-scaffold/internal/service"
+	"go-api-scaffold/pkg/logger"
+)
+
+// seedAdmin creates the configured admin account when no user exists yet
+func seedAdmin(ctx context.Context, env *Env) (Result, error) {
+	if env.Admin.Password == "" {
+		return Result{}, nil
+	}
+
+	var count int64
+	if err := env.DB.DB().WithContext(ctx).Model(&model.User{}).Count(&count).Error; err != nil {
+		return Result{}, err
+	}
+	if count > 0 {
+		return Result{Existing: 1}, nil
+	}
+
+	if _, err := service.NewUserService(env.DB).Create(ctx, env.Admin.Username, env.Admin.Password, model.RoleAdmin); err != nil {
+		return Result{}, err
+	}
+	logger.Infof("default admin created: %s (password from admin.password)", env.Admin.Username)
+	return Result{Created: 1}, nil
+}
+
+// userFixture is a record of the users section; the password is hashed on creation
+type userFixture struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+// seedUsers creates the fixture accounts that don't exist yet
+func seedUsers(ctx context.Context, env *Env) (Result, error) {
+	var res Result
+	var users []userFixture
+	if err := env.Fixtures.Decode("users", &users); err != nil {
+		return res, err
+	}
+
+	svc := service.NewUserService(env.DB)
+	for _, u := range users {
+		if u.Role == "" {
+			u.Role = model.RoleUser
+		}
+		_, err := svc.Create(ctx, u.Username, u.Password, u.Role)
+		switch {
+		case errors.Is(err, service.ErrUserExists):
+			res.Existing++
+		case err != nil:
+			return res, err
+		default:
+			res.Created++
+		}
+	}
+	return res, nil
+}
@@ -1,12 +1,14 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
 	"go-api-scaffold/internal/model"
 	"go-api-scaffold/internal/store"
 	"go-api-scaffold/pkg/logger"
+	"go-api-scaffold/pkg/tracing"
 
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
@@ -35,25 +37,27 @@ type AuthService struct {
 }
 
 func NewAuthService(db *store.Store, secret string, expireHours, refreshHours int) *AuthService {
-	svc := &AuthService{
+	return &AuthService{
 		db:           db,
 		jwtSecret:    []byte(secret),
 		expireHours:  expireHours,
 		refreshHours: refreshHours,
 	}
-	// Ensure default admin account exists
-	svc.ensureDefaultAdmin()
-	return svc
 }
 
 // Login authenticates a user and returns a token
-func (s *AuthService) Login(username, password string) (*TokenResponse, error) {
+func (s *AuthService) Login(ctx context.Context, username, password string) (resp *TokenResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer func() { tracing.End(span, err) }()
+
 	var user model.User
-	if err := s.db.DB().Where("username = ?", username).First(&user).Error; err != nil {
+	if err := s.db.DB().WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
+		logger.FromContext(ctx).Infow("login failed: unknown user", "username", username)
 		return nil, errors.New("invalid username or password")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		logger.FromContext(ctx).Infow("login failed: wrong password", "username", username)
 		return nil, errors.New("invalid username or password")
 	}
 
@@ -77,14 +81,17 @@ func (s *AuthService) ValidateToken(tokenStr string) (*Claims, error) {
 }
 
 // RefreshToken refreshes a JWT token
-func (s *AuthService) RefreshToken(tokenStr string) (*TokenResponse, error) {
+func (s *AuthService) RefreshToken(ctx context.Context, tokenStr string) (resp *TokenResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.RefreshToken")
+	defer func() { tracing.End(span, err) }()
+
 	claims, err := s.ValidateToken(tokenStr)
 	if err != nil {
 		return nil, err
 	}
 
 	var user model.User
-	if err := s.db.DB().First(&user, claims.UserID).Error; err != nil {
+	if err := s.db.DB().WithContext(ctx).First(&user, claims.UserID).Error; err != nil {
 		return nil, errors.New("user not found")
 	}
 
@@ -115,28 +122,3 @@ func (s *AuthService) generateToken(user *model.User) (*TokenResponse, error) {
 		ExpiresAt: expiresAt.Unix(),
 	}, nil
 }
-
-func (s *AuthService) ensureDefaultAdmin() {
-	var count int64
-	s.db.DB().Model(&model.User{}).Count(&count)
-	if count > 0 {
-		return
-	}
-
-	hashed, err := bcrypt.GenerateFromPassword([]byte("admin123"), bcrypt.DefaultCost)
-	if err != nil {
-		logger.Errorf("failed to create default admin: %v", err)
-		return
-	}
-
-	admin := &model.User{
-		Username: "admin",
-		Password: string(hashed),
-		Role:     "admin",
-	}
-	if err := s.db.DB().Create(admin).Error; err != nil {
-		logger.Errorf("failed to create default admin: %v", err)
-		return
-	}
-	logger.Info("default admin created: admin / admin123")
-}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-api-scaffold/internal/store"
+	"go-api-scaffold/pkg/config"
+	"go-api-scaffold/pkg/logger"
+)
+
+// backupTimeFormat names snapshots so that they sort by time
+const backupTimeFormat = "20060102-150405.000"
+
+var ErrBackupNotFound = errors.New("backup not found")
+
+// BackupInfo describes a snapshot file
+type BackupInfo struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BackupService takes online snapshots of the sqlite database, restores
+// them and checks the database integrity
+type BackupService struct {
+	db     *store.Store
+	cfg    config.BackupConfig
+	prefix string // snapshot file prefix, from the database file name
+
+	mu sync.Mutex // one backup or restore at a time
+
+	checkMu   sync.Mutex
+	integrity error // result of the last integrity check
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewBackupService(db *store.Store, cfg *config.DatabaseConfig) *BackupService {
+	return &BackupService{
+		db:     db,
+		cfg:    cfg.Backup,
+		prefix: strings.TrimSuffix(filepath.Base(cfg.Path), filepath.Ext(cfg.Path)) + "-",
+	}
+}
+
+// Create writes a snapshot of the live database and prunes old ones
+func (s *BackupService) Create(ctx context.Context) (*BackupInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, err := s.create(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.prune(); err != nil {
+		logger.Warnf("prune backups: %v", err)
+	}
+	return info, nil
+}
+
+// create writes a snapshot into a file it reserves first, so that it never
+// overwrites or, on failure, removes a snapshot it did not write
+func (s *BackupService) create(ctx context.Context, suffix string) (*BackupInfo, error) {
+	if err := os.MkdirAll(s.cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create backup directory: %w", err)
+	}
+	path, err := s.reserve(suffix)
+	if err != nil {
+		return nil, fmt.Errorf("backup: %w", err)
+	}
+	if err := s.db.Backup(ctx, path); err != nil {
+		_ = os.Remove(path)
+		return nil, fmt.Errorf("backup: %w", err)
+	}
+	return stat(path)
+}
+
+// reserve creates an empty snapshot file named after the current time
+// (VACUUM INTO writes into an empty file); a name taken by another backup
+// is retried with a later time
+func (s *BackupService) reserve(suffix string) (string, error) {
+	for attempt := 0; ; attempt++ {
+		name := s.prefix + time.Now().UTC().Format(backupTimeFormat) + suffix + ".db"
+		path := filepath.Join(s.cfg.Dir, name)
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			return path, f.Close()
+		}
+		if !errors.Is(err, os.ErrExist) || attempt == 9 {
+			return "", err
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// List returns the snapshots, newest first
+func (s *BackupService) List() ([]BackupInfo, error) {
+	entries, err := os.ReadDir(s.cfg.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []BackupInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	list := []BackupInfo{}
+	for _, e := range entries {
+		if e.IsDir() || !s.isBackup(e.Name()) {
+			continue
+		}
+		info, err := stat(filepath.Join(s.cfg.Dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *info)
+	}
+	// Names embed the UTC time, so they sort chronologically
+	sort.Slice(list, func(i, j int) bool { return list[i].Name > list[j].Name })
+	return list, nil
+}
+
+// Restore replaces the live database with the named snapshot
+func (s *BackupService) Restore(ctx context.Context, name string) error {
+	if !s.isBackup(name) || filepath.Base(name) != name {
+		return ErrBackupNotFound
+	}
+	path := filepath.Join(s.cfg.Dir, name)
+	if _, err := os.Stat(path); err != nil {
+		return ErrBackupNotFound
+	}
+	return s.RestoreFile(ctx, path)
+}
+
+// RestoreFile replaces the live database with the database file at path,
+// after checking its integrity and snapshotting the current data. Old
+// snapshots are not pruned here, so path is never deleted before it is read.
+func (s *BackupService) RestoreFile(ctx context.Context, path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := store.VerifyBackup(ctx, path); err != nil {
+		return fmt.Errorf("refusing to restore %s: %w", path, err)
+	}
+	pre, err := s.create(ctx, "-pre-restore")
+	if err != nil {
+		return fmt.Errorf("snapshot before restore: %w", err)
+	}
+	if err := s.db.Restore(ctx, path); err != nil {
+		return fmt.Errorf("restore (previous data in %s): %w", pre.Name, err)
+	}
+	logger.Warnf("database restored from %s (previous data in %s)", path, pre.Name)
+	s.CheckIntegrity(ctx)
+	return nil
+}
+
+// CheckIntegrity runs PRAGMA integrity_check and keeps the result for Integrity
+func (s *BackupService) CheckIntegrity(ctx context.Context) error {
+	err := s.db.IntegrityCheck(ctx)
+	if err != nil {
+		logger.Errorf("database %v", err)
+	}
+	s.checkMu.Lock()
+	s.integrity = err
+	s.checkMu.Unlock()
+	return err
+}
+
+// Integrity returns the result of the last integrity check (readiness check)
+func (s *BackupService) Integrity(context.Context) error {
+	s.checkMu.Lock()
+	defer s.checkMu.Unlock()
+	return s.integrity
+}
+
+// Start runs the scheduled snapshots and integrity checks
+func (s *BackupService) Start(context.Context) error {
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run()
+	return nil
+}
+
+// Stop ends the schedule, waiting for a running backup
+func (s *BackupService) Stop(ctx context.Context) error {
+	close(s.stop)
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *BackupService) run() {
+	defer close(s.done)
+
+	var backupC, checkC <-chan time.Time
+	if s.cfg.Enabled {
+		t := time.NewTicker(time.Duration(s.cfg.Interval) * time.Minute)
+		defer t.Stop()
+		backupC = t.C
+		logger.Infof("database backups every %d min to %s (keep %d)", s.cfg.Interval, s.cfg.Dir, s.cfg.Keep)
+	}
+	if s.cfg.IntegrityCheck > 0 {
+		t := time.NewTicker(time.Duration(s.cfg.IntegrityCheck) * time.Minute)
+		defer t.Stop()
+		checkC = t.C
+		s.CheckIntegrity(context.Background())
+	}
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-backupC:
+			if info, err := s.Create(context.Background()); err != nil {
+				logger.Errorf("scheduled %v", err)
+			} else {
+				logger.Infof("database backup written: %s (%d bytes)", info.Name, info.Size)
+			}
+		case <-checkC:
+			s.CheckIntegrity(context.Background())
+		}
+	}
+}
+
+// prune deletes the oldest snapshots beyond cfg.Keep
+func (s *BackupService) prune() error {
+	if s.cfg.Keep <= 0 {
+		return nil
+	}
+	list, err := s.List()
+	if err != nil {
+		return err
+	}
+	for _, b := range list[min(s.cfg.Keep, len(list)):] {
+		if err := os.Remove(filepath.Join(s.cfg.Dir, b.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *BackupService) isBackup(name string) bool {
+	return strings.HasPrefix(name, s.prefix) && strings.HasSuffix(name, ".db")
+}
+
+func stat(path string) (*BackupInfo, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return &BackupInfo{Name: fi.Name(), Size: fi.Size(), CreatedAt: fi.ModTime()}, nil
+}
@@ -1,24 +1,50 @@
 package service
 
 import (
+	"context"
+
+	"go-api-scaffold/internal/jobs"
 	"go-api-scaffold/internal/model"
 	"go-api-scaffold/internal/store"
+	"go-api-scaffold/pkg/eventbus"
+	"go-api-scaffold/pkg/tracing"
+)
+
+// ExampleTopic is the event bus topic for example changes
+const ExampleTopic = "example"
+
+// ExampleImportJob is the job type of bulk imports
+const ExampleImportJob = "example.import"
+
+// Example change event types
+const (
+	EventCreated = "created"
+	EventUpdated = "updated"
+	EventDeleted = "deleted"
 )
 
 // ExampleService handles example business logic
 type ExampleService struct {
+	db   *store.Store
 	repo *store.ExampleRepository
+	bus  *eventbus.Bus
+	jobs *jobs.Manager
 }
 
-func NewExampleService(db *store.Store) *ExampleService {
+func NewExampleService(db *store.Store, bus *eventbus.Bus) *ExampleService {
 	return &ExampleService{
+		db:   db,
 		repo: store.NewExampleRepository(db),
+		bus:  bus,
 	}
 }
 
 // Create creates an example
-func (s *ExampleService) Create(req *model.CreateExampleRequest) (*model.Example, error) {
-	item := &model.Example{
+func (s *ExampleService) Create(ctx context.Context, req *model.CreateExampleRequest) (item *model.Example, err error) {
+	ctx, span := tracing.Start(ctx, "ExampleService.Create")
+	defer func() { tracing.End(span, err) }()
+
+	item = &model.Example{
 		Name:        req.Name,
 		Description: req.Description,
 		Status:      req.Status,
@@ -27,19 +53,67 @@ func (s *ExampleService) Create(req *model.CreateExampleRequest) (*model.Example
 		item.Status = "active"
 	}
 
-	if err := s.repo.Create(item); err != nil {
+	if err := s.repo.Create(ctx, item); err != nil {
 		return nil, err
 	}
+	s.publish(EventCreated, item)
 	return item, nil
 }
 
+// RegisterJobs registers the background jobs of the module
+func (s *ExampleService) RegisterJobs(m *jobs.Manager) {
+	s.jobs = m
+	m.Handle(ExampleImportJob, s.runImport)
+}
+
+// Import enqueues a bulk import; the returned job reports its progress
+func (s *ExampleService) Import(ctx context.Context, req *model.ImportExampleRequest, userID uint) (*model.Job, error) {
+	return s.jobs.Enqueue(ctx, ExampleImportJob, req, jobs.CreatedBy(userID))
+}
+
+// runImport creates the examples of an import job and marks the job
+// succeeded in one transaction, so a retried job never imports twice
+func (s *ExampleService) runImport(ctx context.Context, job *model.Job) error {
+	var req model.ImportExampleRequest
+	if err := jobs.Decode(job, &req); err != nil {
+		return err
+	}
+
+	items := make([]model.Example, len(req.Items))
+	for i, r := range req.Items {
+		items[i] = model.Example{Name: r.Name, Description: r.Description, Status: r.Status}
+		if items[i].Status == "" {
+			items[i].Status = "active"
+		}
+	}
+	err := s.db.Transaction(ctx, func(tx *store.Store) error {
+		if err := store.NewExampleRepository(tx).CreateBatch(ctx, items); err != nil {
+			return err
+		}
+		return jobs.Succeed(ctx, tx, job)
+	})
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		s.publish(EventCreated, &items[i])
+	}
+	return nil
+}
+
 // GetByID returns an example by ID
-func (s *ExampleService) GetByID(id uint) (*model.Example, error) {
-	return s.repo.FindByID(id)
+func (s *ExampleService) GetByID(ctx context.Context, id uint) (item *model.Example, err error) {
+	ctx, span := tracing.Start(ctx, "ExampleService.GetByID")
+	defer func() { tracing.End(span, err) }()
+
+	return s.repo.FindByID(ctx, id)
 }
 
 // List returns a paginated list of examples
-func (s *ExampleService) List(req *model.QueryExampleRequest) ([]model.Example, int64, error) {
+func (s *ExampleService) List(ctx context.Context, req *model.QueryExampleRequest) (items []model.Example, total int64, err error) {
+	ctx, span := tracing.Start(ctx, "ExampleService.List")
+	defer func() { tracing.End(span, err) }()
+
 	if req.Page < 1 {
 		req.Page = 1
 	}
@@ -49,12 +123,15 @@ func (s *ExampleService) List(req *model.QueryExampleRequest) ([]model.Example,
 	if req.PageSize > 100 {
 		req.PageSize = 100
 	}
-	return s.repo.List(req.Page, req.PageSize, req.Keyword, req.Status)
+	return s.repo.List(ctx, req.Page, req.PageSize, req.Keyword, req.Status)
 }
 
 // Update updates an example
-func (s *ExampleService) Update(id uint, req *model.UpdateExampleRequest) (*model.Example, error) {
-	item, err := s.repo.FindByID(id)
+func (s *ExampleService) Update(ctx context.Context, id uint, req *model.UpdateExampleRequest) (item *model.Example, err error) {
+	ctx, span := tracing.Start(ctx, "ExampleService.Update")
+	defer func() { tracing.End(span, err) }()
+
+	item, err = s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -69,13 +146,37 @@ func (s *ExampleService) Update(id uint, req *model.UpdateExampleRequest) (*mode
 		item.Status = *req.Status
 	}
 
-	if err := s.repo.Update(item); err != nil {
+	if err := s.repo.Update(ctx, item); err != nil {
 		return nil, err
 	}
+	s.publish(EventUpdated, item)
 	return item, nil
 }
 
 // Delete removes an example
-func (s *ExampleService) Delete(id uint) error {
-	return s.repo.Delete(id)
+func (s *ExampleService) Delete(ctx context.Context, id uint) (err error) {
+	ctx, span := tracing.Start(ctx, "ExampleService.Delete")
+	defer func() { tracing.End(span, err) }()
+
+	item, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.publish(EventDeleted, item)
+	return nil
+}
+
+// Watch subscribes to example changes after revision since (0 = new changes only).
+// Payloads are *model.Example; returns eventbus.ErrRevisionUnavailable when
+// since can no longer be resumed and the caller must relist.
+func (s *ExampleService) Watch(since uint64) (*eventbus.Subscription, error) {
+	return s.bus.Subscribe(ExampleTopic, since, 64)
+}
+
+func (s *ExampleService) publish(typ string, item *model.Example) {
+	snapshot := *item
+	s.bus.Publish(ExampleTopic, typ, &snapshot)
 }
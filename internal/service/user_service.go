@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-api-scaffold/internal/model"
+	"go-api-scaffold/internal/store"
+	"go-api-scaffold/pkg/tracing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinPasswordLength is the shortest password accepted for an account
+const MinPasswordLength = 8
+
+var (
+	ErrUserExists   = errors.New("user already exists")
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// UserService manages accounts
+type UserService struct {
+	db *store.Store
+}
+
+func NewUserService(db *store.Store) *UserService {
+	return &UserService{db: db}
+}
+
+// Create creates an account with a hashed password
+func (s *UserService) Create(ctx context.Context, username, password, role string) (user *model.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.Create")
+	defer func() { tracing.End(span, err) }()
+
+	if username == "" {
+		return nil, errors.New("username is required")
+	}
+	if role != model.RoleAdmin && role != model.RoleUser {
+		return nil, fmt.Errorf("unsupported role %q (want %s or %s)", role, model.RoleAdmin, model.RoleUser)
+	}
+	hashed, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := s.db.DB().WithContext(ctx).Model(&model.User{}).Where("username = ?", username).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrUserExists
+	}
+
+	user = &model.User{Username: username, Password: hashed, Role: role}
+	if err := s.db.DB().WithContext(ctx).Create(user).Error; err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ResetPassword replaces the password of an account
+func (s *UserService) ResetPassword(ctx context.Context, username, password string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService.ResetPassword")
+	defer func() { tracing.End(span, err) }()
+
+	hashed, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	res := s.db.DB().WithContext(ctx).Model(&model.User{}).Where("username = ?", username).Update("password", hashed)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// List returns all accounts ordered by ID
+func (s *UserService) List(ctx context.Context) (users []model.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.List")
+	defer func() { tracing.End(span, err) }()
+
+	err = s.db.DB().WithContext(ctx).Order("id").Find(&users).Error
+	return users, err
+}
+
+func hashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength {
+		return "", fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
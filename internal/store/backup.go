@@ -0,0 +1,133 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// ErrNotSQLite is returned by the backup operations on other databases
+var ErrNotSQLite = errors.New("backups are only supported for sqlite")
+
+// IsSQLite reports whether the store is a sqlite database
+func (s *Store) IsSQLite() bool {
+	return s.db.Dialector.Name() == "sqlite"
+}
+
+// Backup writes a consistent copy of the live database to path (VACUUM INTO).
+// Writers are not blocked; path must not exist.
+func (s *Store) Backup(ctx context.Context, path string) error {
+	if !s.IsSQLite() {
+		return ErrNotSQLite
+	}
+	return s.db.WithContext(ctx).Exec("VACUUM INTO ?", path).Error
+}
+
+// Restore replaces the content of the live database with the database at
+// path, using SQLite's online backup API. Open connections keep working and
+// see the restored data.
+func (s *Store) Restore(ctx context.Context, path string) error {
+	if !s.IsSQLite() {
+		return ErrNotSQLite
+	}
+
+	src, err := openReadOnly(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	srcConn, err := src.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer srcConn.Close()
+
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	dstConn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer dstConn.Close()
+
+	return dstConn.Raw(func(dst interface{}) error {
+		return srcConn.Raw(func(src interface{}) error {
+			b, err := dst.(*sqlite3.SQLiteConn).Backup("main", src.(*sqlite3.SQLiteConn), "main")
+			if err != nil {
+				return err
+			}
+			if _, err := b.Step(-1); err != nil {
+				_ = b.Finish()
+				return err
+			}
+			return b.Finish()
+		})
+	})
+}
+
+// IntegrityCheck runs PRAGMA integrity_check on the live database
+func (s *Store) IntegrityCheck(ctx context.Context) error {
+	if !s.IsSQLite() {
+		return ErrNotSQLite
+	}
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return integrityCheck(ctx, sqlDB)
+}
+
+// VerifyBackup runs PRAGMA integrity_check on the database file at path
+func VerifyBackup(ctx context.Context, path string) error {
+	db, err := openReadOnly(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return integrityCheck(ctx, db)
+}
+
+func openReadOnly(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	// sql.Open is lazy; fail on a missing or non-sqlite file here
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	return db, nil
+}
+
+func integrityCheck(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return err
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("integrity check failed: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
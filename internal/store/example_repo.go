@@ -1,11 +1,17 @@
 package store
 
 import (
+	"context"
+
 	"go-api-scaffold/internal/model"
 
 	"gorm.io/gorm"
 )
 
+// exampleBatchSize keeps the bound variables of a batch insert (5 per row)
+// below the limit of old sqlite builds (999)
+const exampleBatchSize = 150
+
 // ExampleRepository is the example data repository
 type ExampleRepository struct {
 	db *gorm.DB
@@ -16,25 +22,33 @@ func NewExampleRepository(s *Store) *ExampleRepository {
 }
 
 // Create creates an example
-func (r *ExampleRepository) Create(item *model.Example) error {
-	return r.db.Create(item).Error
+func (r *ExampleRepository) Create(ctx context.Context, item *model.Example) error {
+	return r.db.WithContext(ctx).Create(item).Error
+}
+
+// CreateBatch creates examples with one statement per exampleBatchSize rows,
+// all or none
+func (r *ExampleRepository) CreateBatch(ctx context.Context, items []model.Example) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(&items, exampleBatchSize).Error
+	})
 }
 
 // FindByID returns an example by ID
-func (r *ExampleRepository) FindByID(id uint) (*model.Example, error) {
+func (r *ExampleRepository) FindByID(ctx context.Context, id uint) (*model.Example, error) {
 	var item model.Example
-	if err := r.db.First(&item, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&item, id).Error; err != nil {
 		return nil, err
 	}
 	return &item, nil
 }
 
 // List returns a paginated list of examples
-func (r *ExampleRepository) List(page, pageSize int, keyword, status string) ([]model.Example, int64, error) {
+func (r *ExampleRepository) List(ctx context.Context, page, pageSize int, keyword, status string) ([]model.Example, int64, error) {
 	var items []model.Example
 	var total int64
 
-	query := r.db.Model(&model.Example{})
+	query := r.db.WithContext(ctx).Model(&model.Example{})
 
 	// Filter conditions
 	if keyword != "" {
@@ -59,11 +73,11 @@ func (r *ExampleRepository) List(page, pageSize int, keyword, status string) ([]
 }
 
 // Update updates an example
-func (r *ExampleRepository) Update(item *model.Example) error {
-	return r.db.Save(item).Error
+func (r *ExampleRepository) Update(ctx context.Context, item *model.Example) error {
+	return r.db.WithContext(ctx).Save(item).Error
 }
 
 // Delete removes an example by ID
-func (r *ExampleRepository) Delete(id uint) error {
-	return r.db.Delete(&model.Example{}, id).Error
+func (r *ExampleRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.Example{}, id).Error
 }
@@ -0,0 +1,244 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go-api-scaffold/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// JobRepository is the job queue repository. State changes of a running job
+// are conditioned on its attempt number, so a worker whose job was requeued
+// meanwhile (see RequeueStale) cannot overwrite the new run. Times are stored
+// in UTC: sqlite compares them as text.
+type JobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(s *Store) *JobRepository {
+	return &JobRepository{db: s.DB()}
+}
+
+// Create enqueues a job
+func (r *JobRepository) Create(ctx context.Context, job *model.Job) error {
+	job.RunAt = job.RunAt.UTC()
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// FindByID returns a job by ID
+func (r *JobRepository) FindByID(ctx context.Context, id uint) (*model.Job, error) {
+	var job model.Job
+	if err := r.db.WithContext(ctx).First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// List returns a paginated list of jobs, newest first
+func (r *JobRepository) List(ctx context.Context, page, pageSize int, queue, typ, status string) ([]model.Job, int64, error) {
+	var jobs []model.Job
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.Job{})
+	if queue != "" {
+		query = query.Where("queue = ?", queue)
+	}
+	if typ != "" {
+		query = query.Where("type = ?", typ)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&jobs).Error; err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
+
+// Claim marks up to n due jobs of the queue as running by worker and returns
+// them. Each job is taken with a conditional update, so concurrent workers on
+// any database never claim the same job.
+func (r *JobRepository) Claim(ctx context.Context, queue, worker string, now time.Time, n int) ([]model.Job, error) {
+	now = now.UTC()
+	var due []model.Job
+	err := r.db.WithContext(ctx).
+		Where("queue = ? AND status = ? AND run_at <= ?", queue, model.JobPending, now).
+		Order("run_at, id").Limit(n).Find(&due).Error
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := due[:0]
+	for _, job := range due {
+		res := r.db.WithContext(ctx).Model(&model.Job{}).
+			Where("id = ? AND status = ?", job.ID, model.JobPending).
+			Updates(map[string]interface{}{
+				"status":    model.JobRunning,
+				"attempts":  gorm.Expr("attempts + 1"),
+				"locked_by": worker,
+				"locked_at": now,
+			})
+		if res.Error != nil {
+			return claimed, res.Error
+		}
+		if res.RowsAffected == 0 {
+			continue // taken by another worker
+		}
+		job.Status = model.JobRunning
+		job.Attempts++
+		job.LockedBy = worker
+		job.LockedAt = &now
+		claimed = append(claimed, job)
+	}
+	return claimed, nil
+}
+
+// Succeed marks a running job as succeeded. It reports false when this run
+// no longer holds the job (requeued meanwhile, or already succeeded).
+func (r *JobRepository) Succeed(ctx context.Context, job *model.Job) (bool, error) {
+	now := time.Now().UTC()
+	return r.finish(ctx, job, map[string]interface{}{
+		"status":      model.JobSucceeded,
+		"last_error":  "",
+		"finished_at": now,
+	})
+}
+
+// Retry puts a failed job back in the queue until runAt
+func (r *JobRepository) Retry(ctx context.Context, job *model.Job, runAt time.Time, errMsg string) error {
+	_, err := r.finish(ctx, job, map[string]interface{}{
+		"status":     model.JobPending,
+		"run_at":     runAt.UTC(),
+		"last_error": errMsg,
+	})
+	return err
+}
+
+// Bury moves a failed job to the dead letters
+func (r *JobRepository) Bury(ctx context.Context, job *model.Job, errMsg string) error {
+	_, err := r.finish(ctx, job, map[string]interface{}{
+		"status":      model.JobDead,
+		"last_error":  errMsg,
+		"finished_at": time.Now().UTC(),
+	})
+	return err
+}
+
+// Release returns an interrupted job to the queue without counting the attempt
+func (r *JobRepository) Release(ctx context.Context, job *model.Job) error {
+	_, err := r.finish(ctx, job, map[string]interface{}{
+		"status":   model.JobPending,
+		"attempts": gorm.Expr("attempts - 1"),
+		"run_at":   time.Now().UTC(),
+	})
+	return err
+}
+
+// finish updates a job this worker is running, unlocking it. It reports
+// false when the job is no longer in this run.
+func (r *JobRepository) finish(ctx context.Context, job *model.Job, values map[string]interface{}) (bool, error) {
+	values["locked_by"] = ""
+	values["locked_at"] = nil
+	res := r.db.WithContext(ctx).Model(&model.Job{}).
+		Where("id = ? AND status = ? AND attempts = ?", job.ID, model.JobRunning, job.Attempts).
+		Updates(values)
+	return res.RowsAffected > 0, res.Error
+}
+
+// RequeueStale recovers the jobs of crashed workers: jobs running since
+// before are requeued, or dead-lettered when that was their last attempt
+func (r *JobRepository) RequeueStale(ctx context.Context, before time.Time) (int64, error) {
+	const lost = "worker lost (job timed out or instance stopped)"
+	var n int64
+	before = before.UTC()
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Job{}).
+			Where("status = ? AND locked_at < ? AND attempts >= max_attempts", model.JobRunning, before).
+			Updates(map[string]interface{}{
+				"status": model.JobDead, "last_error": lost, "finished_at": time.Now().UTC(), "locked_by": "", "locked_at": nil,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		n = res.RowsAffected
+		res = tx.Model(&model.Job{}).
+			Where("status = ? AND locked_at < ?", model.JobRunning, before).
+			Updates(map[string]interface{}{
+				"status": model.JobPending, "last_error": lost, "run_at": time.Now().UTC(), "locked_by": "", "locked_at": nil,
+			})
+		n += res.RowsAffected
+		return res.Error
+	})
+	return n, err
+}
+
+// Requeue makes a dead or cancelled job pending again with fresh attempts.
+// It reports false when the job is in another state.
+func (r *JobRepository) Requeue(ctx context.Context, id uint) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.Job{}).
+		Where("id = ? AND status IN ?", id, []string{model.JobDead, model.JobCancelled}).
+		Updates(map[string]interface{}{
+			"status":      model.JobPending,
+			"attempts":    0,
+			"run_at":      time.Now().UTC(),
+			"finished_at": nil,
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+// Cancel cancels a pending job. It reports false when the job is in another state.
+func (r *JobRepository) Cancel(ctx context.Context, id uint) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.Job{}).
+		Where("id = ? AND status = ?", id, model.JobPending).
+		Updates(map[string]interface{}{
+			"status":      model.JobCancelled,
+			"finished_at": time.Now().UTC(),
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+// Purge deletes succeeded and cancelled jobs finished before; dead letters are kept
+func (r *JobRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Where("status IN ? AND finished_at < ?", []string{model.JobSucceeded, model.JobCancelled}, before.UTC()).
+		Delete(&model.Job{})
+	return res.RowsAffected, res.Error
+}
+
+// Stats counts the jobs by queue and status
+func (r *JobRepository) Stats(ctx context.Context) ([]model.JobStats, error) {
+	var rows []struct {
+		Queue  string
+		Status string
+		Count  int
+	}
+	err := r.db.WithContext(ctx).Model(&model.Job{}).
+		Select("queue, status, COUNT(*) AS count").
+		Group("queue, status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byQueue := map[string]map[string]int{}
+	for _, row := range rows {
+		if byQueue[row.Queue] == nil {
+			byQueue[row.Queue] = map[string]int{}
+		}
+		byQueue[row.Queue][row.Status] = row.Count
+	}
+	stats := make([]model.JobStats, 0, len(byQueue))
+	for queue, counts := range byQueue {
+		stats = append(stats, model.JobStats{Queue: queue, Counts: counts})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Queue < stats[j].Queue })
+	return stats, nil
+}
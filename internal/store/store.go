@@ -1,6 +1,8 @@
 package store
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -53,6 +55,10 @@ func New(cfg *config.DatabaseConfig) (*Store, error) {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
 
+	if err := registerTracing(db, cfg.Type); err != nil {
+		return nil, fmt.Errorf("register tracing: %w", err)
+	}
+
 	// SQLite optimization
 	if cfg.Type == "sqlite" {
 		db.Exec("PRAGMA journal_mode=WAL")
@@ -89,6 +95,29 @@ func (s *Store) DB() *gorm.DB {
 	return s.db
 }
 
+// Transaction runs fn with a Store bound to one transaction: the writes of
+// repositories created from tx are committed together, or rolled back when
+// fn returns an error
+func (s *Store) Transaction(ctx context.Context, fn func(tx *Store) error) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&Store{db: tx})
+	})
+}
+
+// SQLDB returns the underlying connection pool
+func (s *Store) SQLDB() (*sql.DB, error) {
+	return s.db.DB()
+}
+
+// Ping checks that the database is reachable
+func (s *Store) Ping(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
 // Close closes the database connection
 func (s *Store) Close() {
 	if sqlDB, err := s.db.DB(); err == nil {
@@ -101,6 +130,9 @@ func (s *Store) AutoMigrate() error {
 	return s.db.AutoMigrate(
 		&model.User{},
 		&model.Example{},
+		&model.Job{},
+		&model.ScheduledTask{},
+		&model.TaskRun{},
 		// GEN:MODEL_MIGRATE - Auto-appended by code generator, do not remove
 	)
 }
@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"go-api-scaffold/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TaskRepository stores the state and run history of periodic tasks. Times
+// are stored in UTC: sqlite compares them as text.
+type TaskRepository struct {
+	db *gorm.DB
+}
+
+func NewTaskRepository(s *Store) *TaskRepository {
+	return &TaskRepository{db: s.DB()}
+}
+
+// Ensure creates the state rows of tasks that have none
+func (r *TaskRepository) Ensure(ctx context.Context, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	tasks := make([]model.ScheduledTask, len(names))
+	for i, name := range names {
+		tasks[i] = model.ScheduledTask{Name: name}
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&tasks).Error
+}
+
+// List returns the state of all tasks by name
+func (r *TaskRepository) List(ctx context.Context) (map[string]model.ScheduledTask, error) {
+	var tasks []model.ScheduledTask
+	if err := r.db.WithContext(ctx).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	byName := make(map[string]model.ScheduledTask, len(tasks))
+	for _, t := range tasks {
+		byName[t.Name] = t
+	}
+	return byName, nil
+}
+
+// SetPaused pauses or resumes a task on all replicas
+func (r *TaskRepository) SetPaused(ctx context.Context, name string, paused bool) error {
+	return r.db.WithContext(ctx).Model(&model.ScheduledTask{}).
+		Where("name = ?", name).
+		Update("paused", paused).Error
+}
+
+// Acquire locks a task for instance until the given time. With a slot, the
+// task must not be paused and the slot not yet claimed by any replica.
+// It reports false when the task is locked or the slot was taken.
+func (r *TaskRepository) Acquire(ctx context.Context, name, instance string, slot *time.Time, until time.Time) (bool, error) {
+	now := time.Now().UTC()
+	values := map[string]interface{}{"locked_by": instance, "locked_until": until.UTC()}
+	query := r.db.WithContext(ctx).Model(&model.ScheduledTask{}).
+		Where("name = ? AND (locked_until IS NULL OR locked_until < ?)", name, now)
+	if slot != nil {
+		query = query.Where("paused = ? AND (last_slot IS NULL OR last_slot < ?)", false, slot.UTC())
+		values["last_slot"] = slot.UTC()
+	}
+	res := query.Updates(values)
+	return res.RowsAffected > 0, res.Error
+}
+
+// Release unlocks a task after a run and records its outcome
+func (r *TaskRepository) Release(ctx context.Context, name, instance string, run *model.TaskRun) error {
+	return r.db.WithContext(ctx).Model(&model.ScheduledTask{}).
+		Where("name = ? AND locked_by = ?", name, instance).
+		Updates(map[string]interface{}{
+			"locked_by":    "",
+			"locked_until": nil,
+			"last_run_at":  run.StartedAt,
+			"last_status":  run.Status,
+			"last_error":   run.Error,
+		}).Error
+}
+
+// CreateRun records the start of a run
+func (r *TaskRepository) CreateRun(ctx context.Context, run *model.TaskRun) error {
+	run.StartedAt = run.StartedAt.UTC()
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+// FinishRun records the outcome of a run
+func (r *TaskRepository) FinishRun(ctx context.Context, run *model.TaskRun) error {
+	return r.db.WithContext(ctx).Model(run).
+		Select("status", "error", "finished_at", "duration_ms").
+		Updates(run).Error
+}
+
+// ListRuns returns a paginated run history of a task, newest first
+func (r *TaskRepository) ListRuns(ctx context.Context, task string, page, pageSize int) ([]model.TaskRun, int64, error) {
+	var runs []model.TaskRun
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.TaskRun{}).Where("task = ?", task)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
+
+// FailLostRuns marks runs still "running" since before as failed: their instance stopped
+func (r *TaskRepository) FailLostRuns(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Model(&model.TaskRun{}).
+		Where("status = ? AND started_at < ?", model.TaskRunning, before.UTC()).
+		Updates(map[string]interface{}{"status": model.TaskFailed, "error": "instance lost during the run"})
+	return res.RowsAffected, res.Error
+}
+
+// PurgeRuns deletes the history of runs started before
+func (r *TaskRepository) PurgeRuns(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Where("started_at < ? AND status <> ?", before.UTC(), model.TaskRunning).
+		Delete(&model.TaskRun{})
+	return res.RowsAffected, res.Error
+}
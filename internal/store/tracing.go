@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"go-api-scaffold/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const tracingParentKey = "tracing:parent_ctx"
+
+// registerTracing adds GORM callbacks that wrap each query in a client span.
+// Spans are only created inside an existing trace (pass ctx via db.WithContext).
+func registerTracing(db *gorm.DB, system string) error {
+	before := func(op string) func(*gorm.DB) {
+		return func(tx *gorm.DB) { startSpan(tx, system, op) }
+	}
+
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("tracing:before_create", before("create")),
+		cb.Create().After("gorm:create").Register("tracing:after_create", endSpan),
+		cb.Query().Before("gorm:query").Register("tracing:before_query", before("query")),
+		cb.Query().After("gorm:query").Register("tracing:after_query", endSpan),
+		cb.Update().Before("gorm:update").Register("tracing:before_update", before("update")),
+		cb.Update().After("gorm:update").Register("tracing:after_update", endSpan),
+		cb.Delete().Before("gorm:delete").Register("tracing:before_delete", before("delete")),
+		cb.Delete().After("gorm:delete").Register("tracing:after_delete", endSpan),
+		cb.Row().Before("gorm:row").Register("tracing:before_row", before("row")),
+		cb.Row().After("gorm:row").Register("tracing:after_row", endSpan),
+		cb.Raw().Before("gorm:raw").Register("tracing:before_raw", before("raw")),
+		cb.Raw().After("gorm:raw").Register("tracing:after_raw", endSpan),
+	)
+}
+
+func startSpan(tx *gorm.DB, system, op string) {
+	parent := tx.Statement.Context
+	if parent == nil || !trace.SpanContextFromContext(parent).IsValid() {
+		return
+	}
+	ctx, _ := tracing.Tracer().Start(parent, "gorm."+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system", system)),
+	)
+	// Chained calls (Count then Find) share the statement; restore the parent afterwards
+	tx.InstanceSet(tracingParentKey, parent)
+	tx.Statement.Context = ctx
+}
+
+func endSpan(tx *gorm.DB) {
+	v, ok := tx.InstanceGet(tracingParentKey)
+	if !ok {
+		return
+	}
+	span := trace.SpanFromContext(tx.Statement.Context)
+	tx.Statement.Context = v.(context.Context)
+
+	span.SetAttributes(
+		attribute.String("db.statement", tx.Statement.SQL.String()),
+		attribute.String("db.sql.table", tx.Statement.Table),
+		attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+	)
+	err := tx.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	tracing.End(span, err)
+}
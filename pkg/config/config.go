@@ -2,72 +2,408 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
+	"time"
+
+	"go-api-scaffold/pkg/cron"
 
 	"github.com/spf13/viper"
 )
 
 // Config holds the application configuration
 type Config struct {
-	App      AppConfig      `mapstructure:"app"`
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	GRPC     GRPCConfig     `mapstructure:"grpc"`
-	Log      LogConfig      `mapstructure:"log"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
+	App       AppConfig       `mapstructure:"app"`
+	Server    ServerConfig    `mapstructure:"server"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	GRPC      GRPCConfig      `mapstructure:"grpc"`
+	Log       LogConfig       `mapstructure:"log"`
+	JWT       JWTConfig       `mapstructure:"jwt"`
+	Admin     AdminConfig     `mapstructure:"admin"`
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	CORS      CORSConfig      `mapstructure:"cors"`
+	Metrics   MetricsConfig   `mapstructure:"metrics"`
+	Tracing   TracingConfig   `mapstructure:"tracing"`
+	Health    HealthConfig    `mapstructure:"health"`
+	Shutdown  ShutdownConfig  `mapstructure:"shutdown"`
+	Jobs      JobsConfig      `mapstructure:"jobs"`
+	Scheduler SchedulerConfig `mapstructure:"scheduler"`
 }
 
 type AppConfig struct {
 	Name    string `mapstructure:"name"`
 	Version string `mapstructure:"version"`
 	Mode    string `mapstructure:"mode"` // debug, release, test
+
+	HotReload bool `mapstructure:"hot_reload"` // apply config file changes without a restart where supported
 }
 
 type ServerConfig struct {
-	Host         string `mapstructure:"host"`
-	Port         int    `mapstructure:"port"`
-	ReadTimeout  int    `mapstructure:"read_timeout"`  // seconds
-	WriteTimeout int    `mapstructure:"write_timeout"` // seconds
+	Host           string    `mapstructure:"host"`
+	Port           int       `mapstructure:"port"`
+	ReadTimeout    int       `mapstructure:"read_timeout"`    // seconds
+	WriteTimeout   int       `mapstructure:"write_timeout"`   // seconds
+	RequestTimeout int       `mapstructure:"request_timeout"` // seconds; deadline of REST handlers, 0 = none
+	TLS            TLSConfig `mapstructure:"tls"`             // HTTPS (and h2 via ALPN)
+	// IPs or CIDRs of the reverse proxies whose X-Forwarded-For and X-Real-IP
+	// headers give the client IP; empty trusts none and uses the peer address
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 type GRPCConfig struct {
-	Enabled bool `mapstructure:"enabled"`
-	Port    int  `mapstructure:"port"`
+	Enabled    bool          `mapstructure:"enabled"`
+	Port       int           `mapstructure:"port"`
+	Reflection bool          `mapstructure:"reflection"` // server reflection for grpcurl etc.
+	SharePort  bool          `mapstructure:"share_port"` // serve gRPC on the HTTP port (port and tls ignored)
+	TLS        TLSConfig     `mapstructure:"tls"`
+	Gateway    GatewayConfig `mapstructure:"gateway"`
+	Web        GRPCWebConfig `mapstructure:"web"`
+}
+
+type GRPCWebConfig struct {
+	Enabled bool `mapstructure:"enabled"` // gRPC-Web on the HTTP server (works without grpc.enabled)
+	Connect bool `mapstructure:"connect"` // also accept Connect protocol unary calls
+}
+
+type GatewayConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Prefix  string `mapstructure:"prefix"` // e.g. /gateway -> /gateway/v1/examples
+}
+
+type TLSConfig struct {
+	Enabled      bool   `mapstructure:"enabled"`
+	CertFile     string `mapstructure:"cert_file"`
+	KeyFile      string `mapstructure:"key_file"`
+	ClientCAFile string `mapstructure:"client_ca_file"` // enables mTLS when set
 }
 
 type DatabaseConfig struct {
-	Type            string `mapstructure:"type"` // sqlite, mysql, postgres
-	Host            string `mapstructure:"host"`
-	Port            int    `mapstructure:"port"`
-	User            string `mapstructure:"user"`
-	Password        string `mapstructure:"password"`
-	Database        string `mapstructure:"database"`
-	Path            string `mapstructure:"path"` // SQLite file path
-	MaxOpenConns    int    `mapstructure:"max_open_conns"`
-	MaxIdleConns    int    `mapstructure:"max_idle_conns"`
-	ConnMaxLifetime int    `mapstructure:"conn_max_lifetime"` // minutes
-	AutoMigrate     bool   `mapstructure:"auto_migrate"`
+	Type            string       `mapstructure:"type"` // sqlite, mysql, postgres
+	Host            string       `mapstructure:"host"`
+	Port            int          `mapstructure:"port"`
+	User            string       `mapstructure:"user"`
+	Password        string       `mapstructure:"password" secret:"true"`
+	Database        string       `mapstructure:"database"`
+	Path            string       `mapstructure:"path"` // SQLite file path
+	MaxOpenConns    int          `mapstructure:"max_open_conns"`
+	MaxIdleConns    int          `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime int          `mapstructure:"conn_max_lifetime"` // minutes
+	AutoMigrate     bool         `mapstructure:"auto_migrate"`
+	Backup          BackupConfig `mapstructure:"backup"` // sqlite only
+}
+
+// BackupConfig schedules online snapshots and integrity checks of the sqlite database
+type BackupConfig struct {
+	Enabled        bool   `mapstructure:"enabled"`         // scheduled snapshots (manual backups work regardless)
+	Dir            string `mapstructure:"dir"`             // snapshot directory
+	Interval       int    `mapstructure:"interval"`        // minutes between snapshots
+	Keep           int    `mapstructure:"keep"`            // snapshots kept, the oldest are deleted
+	IntegrityCheck int    `mapstructure:"integrity_check"` // minutes between PRAGMA integrity_check runs (reported on /readyz); 0 disables
 }
 
 type LogConfig struct {
 	Level      string `mapstructure:"level"`       // debug, info, warn, error
-	Format     string `mapstructure:"format"`      // console, json
+	Format     string `mapstructure:"format"`      // console, json (stdout)
+	FileFormat string `mapstructure:"file_format"` // console, json (log file)
 	Output     string `mapstructure:"output"`      // console, file, both
 	FilePath   string `mapstructure:"file_path"`   // log file path
 	MaxSize    int    `mapstructure:"max_size"`    // MB per file
 	MaxBackups int    `mapstructure:"max_backups"` // rotation count
 	MaxAge     int    `mapstructure:"max_age"`     // days retention
 	Compress   bool   `mapstructure:"compress"`    // gzip old logs
+
+	Packages map[string]string `mapstructure:"packages"` // per-package level overrides, e.g. store: debug
+
+	Redact RedactConfig `mapstructure:"redact"`
+
+	Access AccessLogConfig `mapstructure:"access"`
+
+	Sinks []LogSinkConfig `mapstructure:"sinks"` // shipped in addition to output
+}
+
+// LogSinkConfig is an extra log destination
+type LogSinkConfig struct {
+	Type   string           `mapstructure:"type"`   // syslog, http
+	Format string           `mapstructure:"format"` // console, json (default); http always ships JSON
+	Syslog SyslogSinkConfig `mapstructure:"syslog"`
+	HTTP   HTTPSinkConfig   `mapstructure:"http"`
+}
+
+// SyslogSinkConfig sends RFC 5424 messages
+type SyslogSinkConfig struct {
+	Network  string `mapstructure:"network"`  // udp, tcp, unix, unixgram
+	Address  string `mapstructure:"address"`  // host:port or socket path
+	Facility string `mapstructure:"facility"` // user, daemon, local0..local7, ... (default: user)
+	AppName  string `mapstructure:"app_name"` // default: executable name
+}
+
+// HTTPSinkConfig POSTs batches of entries as a JSON array
+type HTTPSinkConfig struct {
+	URL           string            `mapstructure:"url"`
+	Headers       map[string]string `mapstructure:"headers" secret:"true"` // e.g. Authorization
+	BatchSize     int               `mapstructure:"batch_size"`            // entries per request (default 100)
+	FlushInterval int               `mapstructure:"flush_interval"`        // ms between flushes of a partial batch (default 1000)
+	BufferSize    int               `mapstructure:"buffer_size"`           // queued entries; newer ones are dropped when full (default 10000)
+	Timeout       int               `mapstructure:"timeout"`               // seconds per request (default 5)
+	MaxRetries    *int              `mapstructure:"max_retries"`           // retries with exponential backoff before a batch is dropped (default 3, 0 = none)
+}
+
+// SyslogFacilities maps facility names to their RFC 5424 codes
+var SyslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+type RedactConfig struct {
+	Enabled  bool     `mapstructure:"enabled"`
+	Fields   []string `mapstructure:"fields"`   // field names masked entirely (case-insensitive, substring match)
+	Patterns []string `mapstructure:"patterns"` // built-in: jwt, bearer, email, card; other entries are regular expressions
+	Mask     string   `mapstructure:"mask"`
+}
+
+type AccessLogConfig struct {
+	Enabled       bool     `mapstructure:"enabled"`        // replaces the error/slow request logger
+	Fields        []string `mapstructure:"fields"`         // subset of AccessLogFields; empty = all
+	SampleRate    float64  `mapstructure:"sample_rate"`    // share of successful requests logged (0..1); errors and slow requests always are
+	SlowThreshold int      `mapstructure:"slow_threshold"` // milliseconds
+	ExcludePaths  []string `mapstructure:"exclude_paths"`  // path prefixes never logged, e.g. /health
+	FilePath      string   `mapstructure:"file_path"`      // separate JSON file (rotated like log.*); empty = main log
+}
+
+// AccessLogFields are the fields an access log entry can carry
+var AccessLogFields = []string{
+	"request_id", "trace_id", "user_id", "method", "path", "route", "query",
+	"status", "bytes", "latency", "client_ip", "user_agent", "referer",
 }
 
 type JWTConfig struct {
-	Secret       string `mapstructure:"secret"`
+	Secret       string `mapstructure:"secret" secret:"true"`
 	Expire       int    `mapstructure:"expire"`        // hours
 	RefreshHours int    `mapstructure:"refresh_hours"` // refresh window in hours
 }
 
-// Load reads configuration from file
+// AdminConfig is the account created when the users table is empty
+type AdminConfig struct {
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password" secret:"true"` // empty = no account is created
+}
+
+type RateLimitConfig struct {
+	Enabled      bool                     `mapstructure:"enabled"`
+	Backend      string                   `mapstructure:"backend"`        // memory, redis
+	KeyBy        string                   `mapstructure:"key_by"`         // ip, user, api_key (falls back to ip)
+	APIKeyHeader string                   `mapstructure:"api_key_header"` // header used by key_by=api_key
+	Redis        RedisConfig              `mapstructure:"redis"`
+	Default      RateLimitRule            `mapstructure:"default"`
+	Groups       map[string]RateLimitRule `mapstructure:"groups"` // route groups: auth, api, gateway, grpc_web
+}
+
+// RateLimitRule fields left empty in a group inherit from rate_limit.default
+type RateLimitRule struct {
+	Algorithm string `mapstructure:"algorithm"` // token_bucket, sliding_window
+	Limit     int    `mapstructure:"limit"`     // requests per window (bucket size); -1 disables the group
+	Window    int    `mapstructure:"window"`    // seconds
+	KeyBy     string `mapstructure:"key_by"`    // overrides rate_limit.key_by
+}
+
+type RedisConfig struct {
+	Addr     string `mapstructure:"addr"`
+	Password string `mapstructure:"password" secret:"true"`
+	DB       int    `mapstructure:"db"`
+	PoolSize int    `mapstructure:"pool_size"`
+	Prefix   string `mapstructure:"prefix"` // key prefix
+}
+
+// Rule returns the effective rule for a route group
+func (c *RateLimitConfig) Rule(group string) RateLimitRule {
+	rule := c.Default
+	if rule.KeyBy == "" {
+		rule.KeyBy = c.KeyBy
+	}
+	g, ok := c.Groups[group]
+	if !ok {
+		return rule
+	}
+	if g.Algorithm != "" {
+		rule.Algorithm = g.Algorithm
+	}
+	if g.Limit != 0 {
+		rule.Limit = g.Limit
+	}
+	if g.Window != 0 {
+		rule.Window = g.Window
+	}
+	if g.KeyBy != "" {
+		rule.KeyBy = g.KeyBy
+	}
+	return rule
+}
+
+type MetricsConfig struct {
+	Enabled   bool      `mapstructure:"enabled"`
+	Path      string    `mapstructure:"path"`      // scrape endpoint, served without auth
+	Namespace string    `mapstructure:"namespace"` // metric name prefix, e.g. myapp_http_requests_total
+	Buckets   []float64 `mapstructure:"buckets"`   // latency histogram buckets in seconds
+}
+
+// HealthConfig tunes the dependency checks behind /readyz
+type HealthConfig struct {
+	Timeout       int            `mapstructure:"timeout"`        // per-check timeout, ms
+	Timeouts      map[string]int `mapstructure:"timeouts"`       // per-check overrides, ms, e.g. {database: 5000}
+	CacheTTL      int            `mapstructure:"cache_ttl"`      // ms a result is reused; 0 runs checks on every probe
+	MinFreeDisk   int            `mapstructure:"min_free_disk"`  // MB required on the database and log volumes; 0 disables
+	ShutdownDelay int            `mapstructure:"shutdown_delay"` // seconds /readyz fails before the servers stop
+}
+
+// ShutdownConfig bounds graceful shutdown, which starts after health.shutdown_delay
+type ShutdownConfig struct {
+	Timeout int            `mapstructure:"timeout"` // seconds for all components to stop; then only Final hooks run before exit
+	Drain   int            `mapstructure:"drain"`   // seconds each component may take to drain
+	Drains  map[string]int `mapstructure:"drains"`  // per-component overrides, e.g. {http: 20}
+}
+
+// JobsConfig configures the background job workers of this instance
+type JobsConfig struct {
+	Enabled      bool           `mapstructure:"enabled"`       // run workers; jobs can be enqueued either way
+	Queues       map[string]int `mapstructure:"queues"`        // queue name -> concurrent workers
+	PollInterval int            `mapstructure:"poll_interval"` // ms between polls of an idle queue
+	MaxAttempts  int            `mapstructure:"max_attempts"`  // default attempts before a job is dead-lettered
+	Backoff      int            `mapstructure:"backoff"`       // seconds before the first retry, doubled per attempt
+	MaxBackoff   int            `mapstructure:"max_backoff"`   // seconds, cap of the retry delay
+	Timeout      int            `mapstructure:"timeout"`       // seconds a job may run; running jobs older than that are requeued after a crash
+	Retention    int            `mapstructure:"retention"`     // hours succeeded and cancelled jobs are kept (purge_jobs task); 0 keeps them
+}
+
+// SchedulerConfig configures the periodic tasks (see internal/scheduler)
+type SchedulerConfig struct {
+	Enabled   bool              `mapstructure:"enabled"`   // run due tasks in this instance; a database lock lets one replica run each
+	Timezone  string            `mapstructure:"timezone"`  // of cron expressions, e.g. Europe/Berlin; default: local time
+	Timeout   int               `mapstructure:"timeout"`   // seconds a run may take (and holds its lock)
+	Retention int               `mapstructure:"retention"` // hours the run history is kept; 0 keeps it
+	Schedules map[string]string `mapstructure:"schedules"` // per-task schedule overrides; "off" disables a task
+}
+
+type TracingConfig struct {
+	Enabled     bool              `mapstructure:"enabled"`
+	ServiceName string            `mapstructure:"service_name"`          // defaults to app.name
+	Exporter    string            `mapstructure:"exporter"`              // otlp, stdout, file
+	Endpoint    string            `mapstructure:"endpoint"`              // OTLP collector host:port
+	Protocol    string            `mapstructure:"protocol"`              // OTLP transport: grpc, http
+	Insecure    bool              `mapstructure:"insecure"`              // OTLP without TLS
+	Headers     map[string]string `mapstructure:"headers" secret:"true"` // OTLP request headers, e.g. auth tokens
+	FilePath    string            `mapstructure:"file_path"`             // file exporter output (JSON lines)
+	SampleRatio float64           `mapstructure:"sample_ratio"`          // 0..1 for new traces; parent decision is honored
+}
+
+type CORSConfig struct {
+	AllowOrigins     []string       `mapstructure:"allow_origins"` // exact, "https://*.example.com" or "*"; empty = same-origin only
+	AllowMethods     []string       `mapstructure:"allow_methods"`
+	AllowHeaders     []string       `mapstructure:"allow_headers"` // "*" allows any requested header
+	ExposeHeaders    []string       `mapstructure:"expose_headers"`
+	AllowCredentials bool           `mapstructure:"allow_credentials"` // not allowed with origin "*"
+	MaxAge           int            `mapstructure:"max_age"`           // preflight cache, seconds
+	Overrides        []CORSOverride `mapstructure:"overrides"`         // per route group, longest path_prefix wins
+}
+
+// CORSOverride fields left empty inherit from the top-level cors policy
+type CORSOverride struct {
+	PathPrefix       string   `mapstructure:"path_prefix"` // e.g. /gateway
+	AllowOrigins     []string `mapstructure:"allow_origins"`
+	AllowMethods     []string `mapstructure:"allow_methods"`
+	AllowHeaders     []string `mapstructure:"allow_headers"`
+	ExposeHeaders    []string `mapstructure:"expose_headers"`
+	AllowCredentials *bool    `mapstructure:"allow_credentials"`
+	MaxAge           *int     `mapstructure:"max_age"`
+}
+
+// Policy returns the effective policy of an override
+func (c *CORSConfig) Policy(o CORSOverride) CORSConfig {
+	p := *c
+	p.Overrides = nil
+	if len(o.AllowOrigins) > 0 {
+		p.AllowOrigins = o.AllowOrigins
+	}
+	if len(o.AllowMethods) > 0 {
+		p.AllowMethods = o.AllowMethods
+	}
+	if len(o.AllowHeaders) > 0 {
+		p.AllowHeaders = o.AllowHeaders
+	}
+	if len(o.ExposeHeaders) > 0 {
+		p.ExposeHeaders = o.ExposeHeaders
+	}
+	if o.AllowCredentials != nil {
+		p.AllowCredentials = *o.AllowCredentials
+	}
+	if o.MaxAge != nil {
+		p.MaxAge = *o.MaxAge
+	}
+	return p
+}
+
+// Load reads the layered config: path (e.g. configs/config.yaml), then
+// config.<APP_ENV>.yaml and config.local.yaml next to it when present,
+// then APP_ environment variables.
 func Load(path string) (*Config, error) {
+	cfg, _, err := LoadWithSources(path)
+	return cfg, err
+}
+
+// LoadWithSources is Load that also reports where each setting came from
+func LoadWithSources(path string) (*Config, Sources, error) {
+	v, layers, err := readLayers(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	sources := collectSources(v, layers)
+	cfg, err := decode(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, sources, nil
+}
+
+// Layers returns the config files Load reads for path, in merge order
+func Layers(path string) []string {
+	layers := []string{path}
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	var overlays []string
+	if env := os.Getenv(EnvVar); env != "" {
+		overlays = append(overlays, base+"."+env+ext)
+	}
+	overlays = append(overlays, base+".local"+ext)
+	for _, o := range overlays {
+		if _, err := os.Stat(o); err == nil {
+			layers = append(layers, o)
+		}
+	}
+	return layers
+}
+
+// readLayers merges the layer files; later files override earlier ones
+func readLayers(path string) (*viper.Viper, []string, error) {
+	layers := Layers(path)
+	v := newViper(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, nil, fmt.Errorf("read config file: %w", err)
+	}
+	for _, layer := range layers[1:] {
+		v.SetConfigFile(layer)
+		if err := v.MergeInConfig(); err != nil {
+			return nil, nil, fmt.Errorf("read config file %s: %w", layer, err)
+		}
+	}
+	return v, layers, nil
+}
+
+func newViper(path string) *viper.Viper {
 	v := viper.New()
 	v.SetConfigFile(path)
 	v.SetConfigType("yaml")
@@ -77,8 +413,18 @@ func Load(path string) (*Config, error) {
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
 
-	if err := v.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("read config file: %w", err)
+	// AutomaticEnv only covers keys present in the file; bind the rest
+	// so APP_ variables also set settings the file leaves out
+	walkKeys(func(key string, _ reflect.Type) {
+		_ = v.BindEnv(key)
+	})
+	return v
+}
+
+// decode resolves secrets, applies the settings over the defaults and validates them
+func decode(v *viper.Viper) (*Config, error) {
+	if err := resolveSecrets(v); err != nil {
+		return nil, fmt.Errorf("resolve config: %w", err)
 	}
 
 	cfg := DefaultConfig()
@@ -100,16 +446,28 @@ func DefaultConfig() *Config {
 			Name:    "my-service",
 			Version: "1.0.0",
 			Mode:    "debug",
+
+			HotReload: true,
 		},
 		Server: ServerConfig{
-			Host:         "0.0.0.0",
-			Port:         8080,
-			ReadTimeout:  10,
-			WriteTimeout: 10,
+			Host:           "0.0.0.0",
+			Port:           8080,
+			ReadTimeout:    10,
+			WriteTimeout:   10,
+			RequestTimeout: 30,
 		},
 		GRPC: GRPCConfig{
-			Enabled: false,
-			Port:    9090,
+			Enabled:    false,
+			Port:       9090,
+			Reflection: false,
+			Gateway: GatewayConfig{
+				Enabled: false,
+				Prefix:  "/gateway",
+			},
+			Web: GRPCWebConfig{
+				Enabled: false,
+				Connect: true,
+			},
 		},
 		Database: DatabaseConfig{
 			Type:            "sqlite",
@@ -118,31 +476,177 @@ func DefaultConfig() *Config {
 			MaxIdleConns:    10,
 			ConnMaxLifetime: 60,
 			AutoMigrate:     true,
+			Backup: BackupConfig{
+				Enabled:        false,
+				Dir:            "./data/backups",
+				Interval:       1440, // daily
+				Keep:           7,
+				IntegrityCheck: 60,
+			},
 		},
 		Log: LogConfig{
 			Level:      "info",
 			Format:     "console",
+			FileFormat: "json",
 			Output:     "both",
 			FilePath:   "logs/app.log",
 			MaxSize:    50,
 			MaxBackups: 3,
 			MaxAge:     7,
 			Compress:   true,
+			Redact: RedactConfig{
+				Enabled:  true,
+				Fields:   []string{"password", "passwd", "token", "authorization", "secret", "api_key", "cookie"},
+				Patterns: []string{"jwt", "bearer", "email", "card"},
+				Mask:     "***",
+			},
+			Access: AccessLogConfig{
+				Enabled:       false,
+				SampleRate:    1,
+				SlowThreshold: 1000,
+				ExcludePaths:  []string{"/health", "/livez", "/readyz", "/metrics"},
+			},
 		},
 		JWT: JWTConfig{
-			Secret:       "change-me-in-production",
+			Secret:       DefaultJWTSecret,
 			Expire:       24,
 			RefreshHours: 168, // 7 days
 		},
+		Admin: AdminConfig{
+			Username: "admin",
+			Password: DefaultAdminPassword,
+		},
+		Tracing: TracingConfig{
+			Enabled:     false,
+			Exporter:    "otlp",
+			Endpoint:    "localhost:4317",
+			Protocol:    "grpc",
+			Insecure:    true,
+			FilePath:    "./logs/traces.json",
+			SampleRatio: 1,
+		},
+		Metrics: MetricsConfig{
+			Enabled: false,
+			Path:    "/metrics",
+		},
+		Health: HealthConfig{
+			Timeout:     2000,
+			CacheTTL:    1000,
+			MinFreeDisk: 100,
+		},
+		Shutdown: ShutdownConfig{
+			Timeout: 30,
+			Drain:   10,
+		},
+		Jobs: JobsConfig{
+			Enabled:      true,
+			Queues:       map[string]int{"default": 4},
+			PollInterval: 1000,
+			MaxAttempts:  5,
+			Backoff:      10,
+			MaxBackoff:   3600,
+			Timeout:      300,
+			Retention:    168,
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:   true,
+			Timeout:   600,
+			Retention: 720,
+		},
+		CORS: CORSConfig{
+			AllowOrigins: []string{},
+			AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+			AllowHeaders: []string{
+				"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID", "Traceparent", "Tracestate",
+				"X-Grpc-Web", "X-User-Agent", "Grpc-Timeout", "Connect-Protocol-Version", "Connect-Timeout-Ms",
+			},
+			ExposeHeaders: []string{
+				"X-Request-ID", "X-Trace-ID", "X-Total-Count",
+				"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin",
+				"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After",
+			},
+			AllowCredentials: false,
+			MaxAge:           600,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:      false,
+			Backend:      "memory",
+			KeyBy:        "ip",
+			APIKeyHeader: "X-API-Key",
+			Redis: RedisConfig{
+				Addr:     "127.0.0.1:6379",
+				PoolSize: 10,
+				Prefix:   "ratelimit:",
+			},
+			Default: RateLimitRule{
+				Algorithm: "token_bucket",
+				Limit:     100,
+				Window:    60,
+			},
+		},
 	}
 }
 
-// Validate checks configuration validity
+// Validate checks configuration validity. In release mode the production
+// checks also apply and all violations are reported together (see ValidationError).
 func (c *Config) Validate() error {
+	err := c.validate()
+	if c.App.Mode != "release" {
+		return err
+	}
+
+	var violations []string
+	if err != nil {
+		violations = append(violations, err.Error())
+	}
+	violations = append(violations, c.releaseViolations()...)
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Mode: c.App.Mode, Violations: violations}
+}
+
+func (c *Config) validate() error {
 	if c.Server.Port < 1 || c.Server.Port > 65535 {
 		return fmt.Errorf("invalid server port: %d", c.Server.Port)
 	}
 
+	if c.Server.RequestTimeout < 0 {
+		return fmt.Errorf("server.request_timeout must not be negative")
+	}
+
+	if err := c.Server.TLS.validate("server.tls"); err != nil {
+		return err
+	}
+
+	for _, p := range c.Server.TrustedProxies {
+		if net.ParseIP(p) == nil {
+			if _, _, err := net.ParseCIDR(p); err != nil {
+				return fmt.Errorf("invalid server.trusted_proxies entry %q: expected an IP or CIDR", p)
+			}
+		}
+	}
+
+	if c.GRPC.Enabled && c.GRPC.SharePort {
+		if c.GRPC.TLS.Enabled {
+			return fmt.Errorf("grpc.tls is not used with grpc.share_port, configure server.tls instead")
+		}
+	} else if c.GRPC.Enabled {
+		if c.GRPC.Port < 1 || c.GRPC.Port > 65535 {
+			return fmt.Errorf("invalid grpc port: %d", c.GRPC.Port)
+		}
+		if err := c.GRPC.TLS.validate("grpc.tls"); err != nil {
+			return err
+		}
+	}
+
+	if c.GRPC.Gateway.Enabled {
+		p := c.GRPC.Gateway.Prefix
+		if !strings.HasPrefix(p, "/") || strings.TrimSuffix(p, "/") == "" {
+			return fmt.Errorf("invalid grpc.gateway.prefix: %q", p)
+		}
+	}
+
 	switch c.Database.Type {
 	case "sqlite":
 		if c.Database.Path == "" {
@@ -156,9 +660,314 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("unsupported database type: %s", c.Database.Type)
 	}
 
+	if c.Database.Type == "sqlite" {
+		if err := c.Database.Backup.validate(); err != nil {
+			return err
+		}
+	}
+
+	for pkg, level := range c.Log.Packages {
+		switch level {
+		case "debug", "info", "warn", "error":
+		default:
+			return fmt.Errorf("log.packages.%s: unsupported level %q", pkg, level)
+		}
+	}
+
+	if c.Log.Format != "console" && c.Log.Format != "json" {
+		return fmt.Errorf("log.format must be console or json")
+	}
+	if c.Log.FileFormat != "console" && c.Log.FileFormat != "json" {
+		return fmt.Errorf("log.file_format must be console or json")
+	}
+	for i := range c.Log.Sinks {
+		if err := c.Log.Sinks[i].validate(fmt.Sprintf("log.sinks[%d]", i)); err != nil {
+			return err
+		}
+	}
+
+	if c.Log.Access.Enabled {
+		if err := c.Log.Access.validate(); err != nil {
+			return err
+		}
+	}
+
 	if c.JWT.Secret == "" {
 		return fmt.Errorf("jwt.secret is required")
 	}
+	if c.Admin.Password != "" && c.Admin.Username == "" {
+		return fmt.Errorf("admin.username is required when admin.password is set")
+	}
+
+	if c.RateLimit.Enabled {
+		if err := c.RateLimit.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.Metrics.Enabled && !strings.HasPrefix(c.Metrics.Path, "/") {
+		return fmt.Errorf("metrics.path must start with /")
+	}
+
+	if c.Tracing.Enabled {
+		if err := c.Tracing.validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Health.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Shutdown.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Jobs.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Scheduler.validate(); err != nil {
+		return err
+	}
+
+	if err := c.CORS.validate("cors"); err != nil {
+		return err
+	}
+	for i, o := range c.CORS.Overrides {
+		if !strings.HasPrefix(o.PathPrefix, "/") {
+			return fmt.Errorf("cors.overrides[%d].path_prefix must start with /", i)
+		}
+		p := c.CORS.Policy(o)
+		if err := p.validate(fmt.Sprintf("cors.overrides[%d]", i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *TLSConfig) validate(prefix string) error {
+	if !t.Enabled {
+		return nil
+	}
+	if t.CertFile == "" || t.KeyFile == "" {
+		return fmt.Errorf("%s requires cert_file and key_file", prefix)
+	}
+	return nil
+}
+
+func (c *RateLimitConfig) validate() error {
+	switch c.Backend {
+	case "memory":
+	case "redis":
+		if c.Redis.Addr == "" {
+			return fmt.Errorf("rate_limit.redis.addr is required for the redis backend")
+		}
+	default:
+		return fmt.Errorf("unsupported rate_limit.backend: %s", c.Backend)
+	}
+
+	groups := []string{"default"}
+	for name := range c.Groups {
+		groups = append(groups, name)
+	}
+	for _, name := range groups {
+		rule := c.Rule(name)
+		if rule.Limit < 0 {
+			continue
+		}
+		if rule.Algorithm != "token_bucket" && rule.Algorithm != "sliding_window" {
+			return fmt.Errorf("rate_limit %s: unsupported algorithm %q", name, rule.Algorithm)
+		}
+		if rule.Limit == 0 || rule.Window <= 0 {
+			return fmt.Errorf("rate_limit %s: limit and window must be positive", name)
+		}
+		switch rule.KeyBy {
+		case "ip", "user", "api_key":
+		default:
+			return fmt.Errorf("rate_limit %s: unsupported key_by %q", name, rule.KeyBy)
+		}
+	}
+	return nil
+}
+
+func (c *CORSConfig) validate(prefix string) error {
+	for _, origin := range c.AllowOrigins {
+		if origin == "*" {
+			if c.AllowCredentials {
+				return fmt.Errorf("%s: allow_origins \"*\" cannot be combined with allow_credentials", prefix)
+			}
+			continue
+		}
+		u, err := url.Parse(strings.Replace(origin, "://*.", "://wildcard.", 1))
+		if err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" || u.RawQuery != "" || strings.Contains(u.Host, "*") {
+			return fmt.Errorf("%s: invalid origin %q (want scheme://host[:port], optionally scheme://*.domain)", prefix, origin)
+		}
+	}
+	if len(c.AllowMethods) == 0 {
+		return fmt.Errorf("%s.allow_methods is required", prefix)
+	}
+	if c.MaxAge < 0 {
+		return fmt.Errorf("%s.max_age must not be negative", prefix)
+	}
+	return nil
+}
+
+func (c *BackupConfig) validate() error {
+	if c.Dir == "" {
+		return fmt.Errorf("database.backup.dir is required")
+	}
+	if c.Enabled && (c.Interval <= 0 || c.Keep <= 0) {
+		return fmt.Errorf("database.backup.interval and keep must be positive")
+	}
+	if c.IntegrityCheck < 0 {
+		return fmt.Errorf("database.backup.integrity_check must not be negative")
+	}
+	return nil
+}
+
+func (c *HealthConfig) validate() error {
+	if c.Timeout <= 0 {
+		return fmt.Errorf("health.timeout must be positive")
+	}
+	for name, ms := range c.Timeouts {
+		if ms <= 0 {
+			return fmt.Errorf("health.timeouts.%s must be positive", name)
+		}
+	}
+	if c.CacheTTL < 0 || c.MinFreeDisk < 0 || c.ShutdownDelay < 0 {
+		return fmt.Errorf("health.cache_ttl, min_free_disk and shutdown_delay must not be negative")
+	}
+	return nil
+}
+
+func (c *ShutdownConfig) validate() error {
+	if c.Timeout <= 0 || c.Drain <= 0 {
+		return fmt.Errorf("shutdown.timeout and shutdown.drain must be positive")
+	}
+	for name, s := range c.Drains {
+		if s <= 0 {
+			return fmt.Errorf("shutdown.drains.%s must be positive", name)
+		}
+	}
+	return nil
+}
+
+func (c *JobsConfig) validate() error {
+	if c.Enabled && len(c.Queues) == 0 {
+		return fmt.Errorf("jobs.queues must not be empty")
+	}
+	for name, n := range c.Queues {
+		if n <= 0 {
+			return fmt.Errorf("jobs.queues.%s must be positive", name)
+		}
+	}
+	if c.PollInterval <= 0 || c.MaxAttempts <= 0 || c.Timeout <= 0 {
+		return fmt.Errorf("jobs.poll_interval, max_attempts and timeout must be positive")
+	}
+	if c.Backoff < 0 || c.MaxBackoff < c.Backoff || c.Retention < 0 {
+		return fmt.Errorf("jobs.backoff must not be negative nor exceed max_backoff, jobs.retention must not be negative")
+	}
+	return nil
+}
+
+func (c *SchedulerConfig) validate() error {
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		return fmt.Errorf("scheduler.timezone: %w", err)
+	}
+	if c.Timeout <= 0 || c.Retention < 0 {
+		return fmt.Errorf("scheduler.timeout must be positive and scheduler.retention not negative")
+	}
+	for name, spec := range c.Schedules {
+		if spec == "off" {
+			continue
+		}
+		if _, err := cron.Parse(spec); err != nil {
+			return fmt.Errorf("scheduler.schedules.%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (c *TracingConfig) validate() error {
+	switch c.Exporter {
+	case "otlp":
+		if c.Endpoint == "" {
+			return fmt.Errorf("tracing.endpoint is required for the otlp exporter")
+		}
+		if c.Protocol != "grpc" && c.Protocol != "http" {
+			return fmt.Errorf("unsupported tracing.protocol: %s", c.Protocol)
+		}
+	case "stdout":
+	case "file":
+		if c.FilePath == "" {
+			return fmt.Errorf("tracing.file_path is required for the file exporter")
+		}
+	default:
+		return fmt.Errorf("unsupported tracing.exporter: %s", c.Exporter)
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return fmt.Errorf("tracing.sample_ratio must be between 0 and 1")
+	}
+	return nil
+}
+
+func (c *LogSinkConfig) validate(prefix string) error {
+	switch c.Format {
+	case "", "json":
+	case "console":
+		if c.Type == "http" {
+			return fmt.Errorf("%s.format: the http sink only ships json", prefix)
+		}
+	default:
+		return fmt.Errorf("%s.format must be console or json", prefix)
+	}
 
+	switch c.Type {
+	case "syslog":
+		switch c.Syslog.Network {
+		case "udp", "tcp", "unix", "unixgram":
+		default:
+			return fmt.Errorf("%s.syslog.network must be udp, tcp, unix or unixgram", prefix)
+		}
+		if c.Syslog.Address == "" {
+			return fmt.Errorf("%s.syslog.address is required", prefix)
+		}
+		if _, ok := SyslogFacilities[c.Syslog.Facility]; c.Syslog.Facility != "" && !ok {
+			return fmt.Errorf("%s.syslog.facility: unknown facility %q", prefix, c.Syslog.Facility)
+		}
+	case "http":
+		if !strings.HasPrefix(c.HTTP.URL, "http://") && !strings.HasPrefix(c.HTTP.URL, "https://") {
+			return fmt.Errorf("%s.http.url must be an http(s) URL", prefix)
+		}
+		if c.HTTP.BatchSize < 0 || c.HTTP.FlushInterval < 0 || c.HTTP.BufferSize < 0 || c.HTTP.Timeout < 0 || (c.HTTP.MaxRetries != nil && *c.HTTP.MaxRetries < 0) {
+			return fmt.Errorf("%s.http: sizes, intervals and retries must not be negative", prefix)
+		}
+	default:
+		return fmt.Errorf("%s.type must be syslog or http", prefix)
+	}
+	return nil
+}
+
+func (c *AccessLogConfig) validate() error {
+	for _, f := range c.Fields {
+		known := false
+		for _, k := range AccessLogFields {
+			if f == k {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown log.access.fields entry: %s", f)
+		}
+	}
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return fmt.Errorf("log.access.sample_rate must be between 0 and 1")
+	}
+	if c.SlowThreshold < 0 {
+		return fmt.Errorf("log.access.slow_threshold must not be negative")
+	}
 	return nil
 }
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvVar selects the config.<env>.yaml layer, e.g. APP_ENV=prod
+const EnvVar = "APP_ENV"
+
+// secretMask replaces secret values in printed configs
+const secretMask = "***"
+
+// Sources maps settings (dot paths) to where their value came from:
+// "default", a config file, "env APP_..." or "file <path>" for <key>_file
+type Sources map[string]string
+
+// collectSources attributes every setting to the last layer that sets it
+func collectSources(v *viper.Viper, layers []string) Sources {
+	files := make([]*viper.Viper, len(layers))
+	for i, layer := range layers {
+		files[i] = viper.New()
+		files[i].SetConfigFile(layer)
+		files[i].SetConfigType("yaml")
+		_ = files[i].ReadInConfig()
+	}
+
+	sources := make(Sources)
+	walkKeys(func(key string, t reflect.Type) {
+		src := "default"
+		for i := len(files) - 1; i >= 0; i-- {
+			if files[i].InConfig(key) {
+				src = layers[i]
+				break
+			}
+		}
+		if name := envName(key); os.Getenv(name) != "" {
+			src = "env " + name
+		}
+		if t.Kind() == reflect.String {
+			if path := v.GetString(key + "_file"); path != "" {
+				src = "file " + path
+			}
+		}
+		sources[key] = src
+	})
+	return sources
+}
+
+func envName(key string) string {
+	return "APP_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// Print writes cfg as YAML with secrets masked; with sources, each setting
+// is annotated with where it came from
+func Print(w io.Writer, cfg *Config, sources Sources) error {
+	p := &printer{w: w, sources: sources}
+	p.section("", reflect.ValueOf(cfg).Elem(), 0)
+	return p.err
+}
+
+type printer struct {
+	w       io.Writer
+	sources Sources
+	err     error
+}
+
+func (p *printer) section(path string, v reflect.Value, indent int) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("mapstructure"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		key := name
+		if path != "" {
+			key = path + "." + name
+		}
+
+		if f.Type.Kind() == reflect.Struct {
+			p.printf("%s%s:\n", strings.Repeat("  ", indent), name)
+			p.section(key, v.Field(i), indent+1)
+			continue
+		}
+
+		b, err := json.Marshal(plain(v.Field(i), f.Tag.Get("secret") == "true"))
+		if err != nil {
+			p.err = err
+			return
+		}
+		line := fmt.Sprintf("%s%s: %s", strings.Repeat("  ", indent), name, b)
+		if src, ok := p.sources[key]; ok {
+			line += "  # " + src
+		}
+		p.printf("%s\n", line)
+	}
+}
+
+func (p *printer) printf(format string, args ...interface{}) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+// plain converts a setting for JSON (valid YAML flow style): structs become
+// maps keyed by their mapstructure names, secret values are masked
+func plain(v reflect.Value, secret bool) interface{} {
+	switch v.Kind() {
+	case reflect.Struct:
+		out := make(map[string]interface{})
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("mapstructure"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+			out[name] = plain(v.Field(i), t.Field(i).Tag.Get("secret") == "true")
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return map[string]interface{}{}
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = plain(iter.Value(), secret)
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return []interface{}{}
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = plain(v.Index(i), secret)
+		}
+		return out
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return plain(v.Elem(), secret)
+	case reflect.String:
+		if secret && v.String() != "" {
+			return secretMask
+		}
+		return v.String()
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	// DefaultJWTSecret is the placeholder secret shipped in the defaults
+	DefaultJWTSecret = "change-me-in-production"
+	// DefaultAdminPassword is the password of the seeded admin in the defaults
+	DefaultAdminPassword = "admin123"
+	// MinSecretBits is the estimated entropy a secret needs in release mode
+	MinSecretBits = 128
+)
+
+// ValidationError lists every violation found by Validate in release mode
+type ValidationError struct {
+	Mode       string
+	Violations []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%d problem(s) in %s mode:\n  - %s", len(e.Violations), e.Mode, strings.Join(e.Violations, "\n  - "))
+}
+
+// releaseViolations checks settings that are only acceptable in development
+func (c *Config) releaseViolations() []string {
+	var v []string
+
+	switch {
+	case c.JWT.Secret == DefaultJWTSecret:
+		v = append(v, "jwt.secret is the default placeholder; set a unique secret (jwt.secret_file, ${ENV} or enc:)")
+	case secretBits(c.JWT.Secret) < MinSecretBits:
+		v = append(v, fmt.Sprintf("jwt.secret is too weak (~%.0f bits, need %d); use e.g. 32 random bytes, base64-encoded",
+			secretBits(c.JWT.Secret), MinSecretBits))
+	}
+
+	if c.Admin.Password == DefaultAdminPassword {
+		v = append(v, "admin.password is the default; change it or set it empty to skip creating the admin")
+	}
+
+	if c.Database.AutoMigrate {
+		v = append(v, "database.auto_migrate must be off in release mode")
+	}
+
+	// "*" with credentials is rejected in every mode; subdomain wildcards are not
+	checkCORS := func(name string, p CORSConfig) {
+		if !p.AllowCredentials {
+			return
+		}
+		for _, origin := range p.AllowOrigins {
+			if strings.Contains(origin, "://*.") {
+				v = append(v, fmt.Sprintf("%s: wildcard origin %q with allow_credentials; list the origins", name, origin))
+			}
+		}
+	}
+	checkCORS("cors", c.CORS)
+	for i, o := range c.CORS.Overrides {
+		checkCORS(fmt.Sprintf("cors.overrides[%d]", i), c.CORS.Policy(o))
+	}
+
+	return v
+}
+
+// secretBits estimates the entropy of s from its length and the character classes it uses
+func secretBits(s string) float64 {
+	var lower, upper, digit, other bool
+	unique := make(map[rune]bool)
+	for _, r := range s {
+		unique[r] = true
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	pool := 0
+	for _, class := range []struct {
+		used bool
+		size int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {other, 33}} {
+		if class.used {
+			pool += class.size
+		}
+	}
+	if pool == 0 {
+		return 0
+	}
+	// Repetitive secrets ("aaaa...") draw from far fewer symbols than their classes suggest
+	pool = min(pool, 2*len(unique))
+	return float64(len([]rune(s))) * math.Log2(float64(pool))
+}
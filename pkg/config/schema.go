@@ -0,0 +1,88 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Schema returns a JSON Schema (draft-07) of the config file, with the
+// defaults of DefaultConfig. Secret settings also accept <key>_file.
+func Schema() ([]byte, error) {
+	root := schemaFor(reflect.TypeOf(Config{}), reflect.ValueOf(*DefaultConfig()))
+	root["$schema"] = "http://json-schema.org/draft-07/schema#"
+	root["title"] = "Service configuration"
+	return json.MarshalIndent(root, "", "  ")
+}
+
+func schemaFor(t reflect.Type, def reflect.Value) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Struct:
+		props := make(map[string]interface{})
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := strings.Split(f.Tag.Get("mapstructure"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+			var fieldDef reflect.Value
+			if def.IsValid() {
+				fieldDef = def.Field(i)
+			}
+			prop := schemaFor(f.Type, fieldDef)
+			if f.Tag.Get("secret") == "true" {
+				delete(prop, "default")
+				if f.Type.Kind() == reflect.String {
+					props[name+"_file"] = map[string]interface{}{
+						"type":        "string",
+						"description": "file containing " + name,
+					}
+				}
+			}
+			props[name] = prop
+		}
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           props,
+			"additionalProperties": false,
+		}
+	case reflect.Map:
+		return withDefault(map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": schemaFor(t.Elem(), reflect.Value{}),
+		}, def)
+	case reflect.Slice:
+		return withDefault(map[string]interface{}{
+			"type":  "array",
+			"items": schemaFor(t.Elem(), reflect.Value{}),
+		}, def)
+	case reflect.Ptr:
+		return schemaFor(t.Elem(), reflect.Value{})
+	case reflect.Bool:
+		return withDefault(scalar("boolean"), def)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return withDefault(scalar("integer"), def)
+	case reflect.Float32, reflect.Float64:
+		return withDefault(scalar("number"), def)
+	default:
+		return withDefault(map[string]interface{}{"type": "string"}, def)
+	}
+}
+
+// scalar accepts a non-string type or a ${VAR} reference, which is a string until resolved
+func scalar(typ string) map[string]interface{} {
+	return map[string]interface{}{
+		"anyOf": []interface{}{
+			map[string]interface{}{"type": typ},
+			map[string]interface{}{"type": "string", "pattern": `^\$\{[A-Za-z_][A-Za-z0-9_]*(:-[^}]*)?\}$`},
+		},
+	}
+}
+
+func withDefault(s map[string]interface{}, def reflect.Value) map[string]interface{} {
+	if def.IsValid() && !def.IsZero() {
+		s["default"] = plain(def, false)
+	}
+	return s
+}
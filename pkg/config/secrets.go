@@ -0,0 +1,245 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// EncryptedPrefix marks values encrypted with the master key
+	EncryptedPrefix = "enc:"
+	// MasterKeyEnv holds the base64 master key; MasterKeyEnv+"_FILE" names a file containing it
+	MasterKeyEnv = "APP_MASTER_KEY"
+)
+
+// envRef matches ${VAR} and ${VAR:-default}
+var envRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// resolveSecrets rewrites the read settings in place:
+//  1. ${VAR} references are replaced by environment variables
+//  2. <key>_file (e.g. jwt.secret_file, APP_JWT_SECRET_FILE) replaces <key> with the file's content
+//  3. enc: values are decrypted with the master key
+func resolveSecrets(v *viper.Viper) error {
+	for _, key := range v.AllKeys() {
+		if err := rewrite(v, key, expandEnv); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range stringKeys() {
+		path := v.GetString(key + "_file")
+		if path == "" {
+			continue
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("%s_file: %w", key, err)
+		}
+		// Secret files usually end with a newline
+		v.Set(key, strings.TrimRight(string(b), "\r\n"))
+	}
+
+	var masterKey []byte
+	decrypt := func(s string) (string, error) {
+		if !strings.HasPrefix(s, EncryptedPrefix) {
+			return s, nil
+		}
+		if masterKey == nil {
+			key, err := MasterKey()
+			if err != nil {
+				return "", err
+			}
+			masterKey = key
+		}
+		return Decrypt(s, masterKey)
+	}
+	for _, key := range v.AllKeys() {
+		if err := rewrite(v, key, decrypt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewrite applies fn to the strings of one setting, overriding it when changed
+func rewrite(v *viper.Viper, key string, fn func(string) (string, error)) error {
+	old := v.Get(key)
+	val, err := walkStrings(old, fn)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	if !reflect.DeepEqual(old, val) {
+		v.Set(key, val)
+	}
+	return nil
+}
+
+func expandEnv(s string) (string, error) {
+	var missing string
+	out := envRef.ReplaceAllStringFunc(s, func(ref string) string {
+		m := envRef.FindStringSubmatch(ref)
+		if val, ok := os.LookupEnv(m[1]); ok {
+			return val
+		}
+		if m[2] != "" {
+			return m[3]
+		}
+		missing = m[1]
+		return ref
+	})
+	if missing != "" {
+		return "", fmt.Errorf("environment variable %s is not set", missing)
+	}
+	return out, nil
+}
+
+// walkStrings applies fn to every string in a settings value
+func walkStrings(val interface{}, fn func(string) (string, error)) (interface{}, error) {
+	switch t := val.(type) {
+	case string:
+		return fn(t)
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, item := range t {
+			v, err := walkStrings(item, fn)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	case []string:
+		out := make([]string, len(t))
+		for i, item := range t {
+			v, err := fn(item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, item := range t {
+			v, err := walkStrings(item, fn)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = v
+		}
+		return out, nil
+	default:
+		return val, nil
+	}
+}
+
+// stringKeys returns the dot paths of all string settings
+func stringKeys() []string {
+	var keys []string
+	walkKeys(func(key string, t reflect.Type) {
+		if t.Kind() == reflect.String {
+			keys = append(keys, key)
+		}
+	})
+	return keys
+}
+
+// walkKeys calls fn with the dot path and type of every setting below the section structs
+func walkKeys(fn func(key string, t reflect.Type)) {
+	var walk func(path string, t reflect.Type)
+	walk = func(path string, t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("mapstructure"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+			if path != "" {
+				name = path + "." + name
+			}
+			if ft := t.Field(i).Type; ft.Kind() == reflect.Struct {
+				walk(name, ft)
+			} else {
+				fn(name, ft)
+			}
+		}
+	}
+	walk("", reflect.TypeOf(Config{}))
+}
+
+// MasterKey reads the master key from APP_MASTER_KEY or the file named by APP_MASTER_KEY_FILE
+func MasterKey() ([]byte, error) {
+	encoded := os.Getenv(MasterKeyEnv)
+	if path := os.Getenv(MasterKeyEnv + "_FILE"); encoded == "" && path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read master key: %w", err)
+		}
+		encoded = strings.TrimSpace(string(b))
+	}
+	if encoded == "" {
+		return nil, fmt.Errorf("encrypted values require %s or %s_FILE", MasterKeyEnv, MasterKeyEnv)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("%s must be 32 bytes, base64-encoded", MasterKeyEnv)
+	}
+	return key, nil
+}
+
+// GenerateKey returns a new base64-encoded master key
+func GenerateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Encrypt seals plaintext with AES-256-GCM and returns an enc: value
+func Encrypt(plaintext string, key []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens an enc: value produced by Encrypt
+func Decrypt(value string, key []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, EncryptedPrefix))
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt failed: wrong master key or corrupted value")
+	}
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDelay coalesces the burst of events an editor save produces
+// and avoids reading a file that is still being written
+const reloadDelay = 200 * time.Millisecond
+
+// ReloadReport describes one reload of the config file
+type ReloadReport struct {
+	Changed         []string // settings that differ from the active config (dot paths)
+	Applied         []string // changed settings handled by a subscriber
+	RestartRequired []string // changed settings that only take effect after a restart
+	Err             error    // read or validation failure (nothing applied) or subscriber failures
+}
+
+// Watcher reloads the config file when it changes and notifies subscribers
+// of the settings they registered for. Subscribing to a nil *Watcher is a no-op.
+type Watcher struct {
+	path   string
+	report func(ReloadReport)
+
+	reloading sync.Mutex // serializes reloads; subscribers run without mu held
+	mu        sync.Mutex
+	current   *Config
+	subs      []subscription
+	timer     *time.Timer
+}
+
+type subscription struct {
+	keys []string
+	fn   func(*Config) error
+}
+
+// Watch starts watching the config files cfg was loaded from (see Layers).
+// report is called after every reload attempt that found changes or failed.
+func Watch(path string, cfg *Config, report func(ReloadReport)) *Watcher {
+	w := &Watcher{path: path, current: cfg, report: report}
+	for _, layer := range Layers(path) {
+		v := newViper(layer)
+		v.OnConfigChange(func(fsnotify.Event) { w.schedule() })
+		v.WatchConfig()
+	}
+	return w
+}
+
+// Subscribe calls fn with the new config when any setting under keys
+// (e.g. "cors", "log.level") changes. Settings without a subscriber are
+// reported as requiring a restart.
+func (w *Watcher) Subscribe(fn func(*Config) error, keys ...string) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subs = append(w.subs, subscription{keys: keys, fn: fn})
+}
+
+// Current returns the active config
+func (w *Watcher) Current() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+func (w *Watcher) schedule() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.timer = time.AfterFunc(reloadDelay, w.reload)
+}
+
+// reload reads and validates the file, then notifies the subscribers of changed settings
+func (w *Watcher) reload() {
+	w.reloading.Lock()
+	defer w.reloading.Unlock()
+
+	v, _, err := readLayers(w.path)
+	if err != nil {
+		w.notify(ReloadReport{Err: err})
+		return
+	}
+	next, err := decode(v)
+	if err != nil {
+		w.notify(ReloadReport{Err: err})
+		return
+	}
+
+	w.mu.Lock()
+	changed := Diff(w.current, next)
+	if len(changed) > 0 {
+		w.current = next
+	}
+	subs := append([]subscription(nil), w.subs...)
+	w.mu.Unlock()
+	if len(changed) == 0 {
+		return
+	}
+
+	rep := ReloadReport{Changed: changed}
+	handled := make(map[string]bool)
+	var errs []error
+	for _, sub := range subs {
+		matched := false
+		for _, key := range changed {
+			if matchesAny(key, sub.keys) {
+				handled[key] = true
+				matched = true
+			}
+		}
+		if !matched {
+			continue
+		}
+		if err := sub.fn(next); err != nil {
+			errs = append(errs, fmt.Errorf("apply %s: %w", strings.Join(sub.keys, ", "), err))
+		}
+	}
+	for _, key := range changed {
+		if handled[key] {
+			rep.Applied = append(rep.Applied, key)
+		} else {
+			rep.RestartRequired = append(rep.RestartRequired, key)
+		}
+	}
+	rep.Err = errors.Join(errs...)
+	w.notify(rep)
+}
+
+func (w *Watcher) notify(rep ReloadReport) {
+	if w.report != nil {
+		w.report(rep)
+	}
+}
+
+// matchesAny reports whether key is one of prefixes or nested below one
+func matchesAny(key string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if key == p || strings.HasPrefix(key, p+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Diff returns the settings (dot paths of mapstructure keys) that differ
+// between two configs. Maps and slices are compared as a whole.
+func Diff(old, next *Config) []string {
+	var changed []string
+	diffValue("", reflect.ValueOf(old).Elem(), reflect.ValueOf(next).Elem(), &changed)
+	sort.Strings(changed)
+	return changed
+}
+
+func diffValue(path string, a, b reflect.Value, changed *[]string) {
+	if a.Kind() != reflect.Struct {
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*changed = append(*changed, path)
+		}
+		return
+	}
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("mapstructure"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+		diffValue(name, a.Field(i), b.Field(i), changed)
+	}
+}
@@ -0,0 +1,193 @@
+// Package cron parses schedules: standard 5-field cron expressions
+// ("*/15 9-17 * * MON-FRI"), the descriptors @yearly, @monthly, @weekly,
+// @daily and @hourly, and fixed intervals ("@every 10m").
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule returns the activation times of a task
+type Schedule interface {
+	// Next returns the first activation strictly after t, in t's location,
+	// or the zero time when there is none within five years
+	Next(t time.Time) time.Time
+}
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a schedule
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if d, ok := strings.CutPrefix(spec, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(d))
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: %w", spec, err)
+		}
+		if interval < time.Second {
+			return nil, fmt.Errorf("cron %q: interval must be at least 1s", spec)
+		}
+		return Every(interval), nil
+	}
+	if expr, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = expr
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q: expected 5 fields (minute hour day-of-month month day-of-week)", spec)
+	}
+	var s expression
+	var err error
+	for i, f := range []struct {
+		out         *uint64
+		first, last int
+		names       []string
+	}{
+		{&s.minute, 0, 59, nil},
+		{&s.hour, 0, 23, nil},
+		{&s.dom, 1, 31, nil},
+		{&s.month, 1, 12, months},
+		{&s.dow, 0, 7, weekdays},
+	} {
+		if *f.out, err = parseField(fields[i], f.first, f.last, f.names); err != nil {
+			return nil, fmt.Errorf("cron %q: field %d: %w", spec, i+1, err)
+		}
+	}
+	// 7 is Sunday too
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domAny = strings.HasPrefix(fields[2], "*")
+	s.dowAny = strings.HasPrefix(fields[4], "*")
+	return &s, nil
+}
+
+var (
+	months   = []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// parseField parses a comma-separated list of *, n, a-b, each with an optional /step
+func parseField(field string, first, last int, names []string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+		}
+
+		lo, hi := first, last
+		if rng != "*" {
+			from, to, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = value(from, first, last, names); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = value(to, first, last, names); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				hi = last // "5/15" means from 5 to the end
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q", rng)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func value(s string, first, last int, names []string) (int, error) {
+	for i, name := range names {
+		if name != "" && strings.EqualFold(s, name) {
+			return i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < first || v > last {
+		return 0, fmt.Errorf("value %q out of range %d-%d", s, first, last)
+	}
+	return v, nil
+}
+
+// expression is a parsed 5-field cron expression, one bit per allowed value
+type expression struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+// Next walks the calendar day by day, so DST transitions neither loop nor
+// skip days; times that do not exist on a transition day are skipped
+func (s *expression) Next(t time.Time) time.Time {
+	loc := t.Location()
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5*366; i, day = i+1, day.AddDate(0, 0, 1) {
+		y, m, d := day.Date()
+		if !s.dayMatches(m, d, day.Weekday()) {
+			continue
+		}
+		for h := 0; h < 24; h++ {
+			if s.hour&(1<<h) == 0 {
+				continue
+			}
+			for mi := 0; mi < 60; mi++ {
+				if s.minute&(1<<mi) == 0 {
+					continue
+				}
+				next := time.Date(y, m, d, h, mi, 0, 0, loc)
+				if next.After(t) && next.Hour() == h && next.Minute() == mi {
+					return next
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches applies the cron rule: when both day fields are restricted, either may match
+func (s *expression) dayMatches(m time.Month, d int, wd time.Weekday) bool {
+	if s.month&(1<<uint(m)) == 0 {
+		return false
+	}
+	domOK := s.dom&(1<<uint(d)) != 0
+	dowOK := s.dow&(1<<uint(wd)) != 0
+	switch {
+	case s.domAny:
+		return dowOK
+	case s.dowAny:
+		return domOK
+	default:
+		return domOK || dowOK
+	}
+}
+
+// Every runs at multiples of d since the Unix epoch, so that all replicas
+// agree on the activation times
+type Every time.Duration
+
+func (e Every) Next(t time.Time) time.Time {
+	d := time.Duration(e)
+	return time.Unix(0, 0).Add(t.Sub(time.Unix(0, 0)).Truncate(d) + d).In(t.Location())
+}
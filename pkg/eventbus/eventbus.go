@@ -0,0 +1,185 @@
+// Package eventbus is an in-process publish/subscribe bus with revision-based resume.
+//
+// Every published event gets a monotonically increasing revision. The bus keeps
+// a bounded history so subscribers can resume after the last revision they saw;
+// revisions are process-local and restart from 1 when the process restarts.
+package eventbus
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrRevisionUnavailable means the requested revision is no longer (or not yet) in history;
+	// the subscriber must resynchronize (e.g. list everything) and watch from 0.
+	ErrRevisionUnavailable = errors.New("revision unavailable")
+	// ErrSlowConsumer means the subscriber's buffer overflowed and it was dropped
+	ErrSlowConsumer = errors.New("subscriber fell behind")
+	// ErrClosed means the bus has been closed
+	ErrClosed = errors.New("event bus closed")
+)
+
+// Event is a published event
+type Event struct {
+	Revision uint64
+	Topic    string
+	Type     string
+	Payload  interface{}
+	Time     time.Time
+}
+
+// Bus is an in-process event bus
+type Bus struct {
+	mu       sync.Mutex
+	revision uint64
+	history  []Event // ring buffer; history[head] is the oldest event
+	head     int
+	size     int
+	subs     map[*Subscription]struct{}
+	closed   bool
+}
+
+// New creates a bus retaining up to historySize events for resume
+func New(historySize int) *Bus {
+	if historySize < 1 {
+		historySize = 1
+	}
+	return &Bus{
+		size: historySize,
+		subs: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish assigns the next revision to an event and delivers it to subscribers of topic.
+// It never blocks: subscribers that cannot keep up are closed with ErrSlowConsumer.
+func (b *Bus) Publish(topic, typ string, payload interface{}) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.revision++
+	ev := Event{
+		Revision: b.revision,
+		Topic:    topic,
+		Type:     typ,
+		Payload:  payload,
+		Time:     time.Now(),
+	}
+	if b.closed {
+		return ev
+	}
+
+	if len(b.history) < b.size {
+		b.history = append(b.history, ev)
+	} else {
+		b.history[b.head] = ev
+		b.head = (b.head + 1) % b.size
+	}
+
+	for sub := range b.subs {
+		if sub.topic != topic {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			b.removeLocked(sub, ErrSlowConsumer)
+		}
+	}
+	return ev
+}
+
+// Revision returns the latest published revision
+func (b *Bus) Revision() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.revision
+}
+
+// Subscribe streams events of topic with a revision greater than since.
+// since == 0 streams only new events. buffer is the channel capacity
+// beyond any replayed history.
+func (b *Bus) Subscribe(topic string, since uint64, buffer int) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, ErrClosed
+	}
+
+	var replay []Event
+	if since > 0 {
+		if since > b.revision {
+			return nil, ErrRevisionUnavailable
+		}
+		if since < b.revision {
+			// The next revision after since must still be retained
+			if len(b.history) == 0 || b.history[b.head].Revision > since+1 {
+				return nil, ErrRevisionUnavailable
+			}
+			for i := range b.history {
+				ev := b.history[(b.head+i)%len(b.history)]
+				if ev.Revision > since && ev.Topic == topic {
+					replay = append(replay, ev)
+				}
+			}
+		}
+	}
+
+	sub := &Subscription{
+		bus:   b,
+		topic: topic,
+		ch:    make(chan Event, len(replay)+buffer),
+	}
+	for _, ev := range replay {
+		sub.ch <- ev
+	}
+	sub.C = sub.ch
+	b.subs[sub] = struct{}{}
+	return sub, nil
+}
+
+// Close closes all subscriptions; later publishes are not delivered
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subs {
+		b.removeLocked(sub, ErrClosed)
+	}
+}
+
+func (b *Bus) removeLocked(sub *Subscription, err error) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+	delete(b.subs, sub)
+	sub.err = err
+	close(sub.ch)
+}
+
+// Subscription receives events on C until it is closed
+type Subscription struct {
+	C <-chan Event
+
+	bus   *Bus
+	topic string
+	ch    chan Event
+	err   error // guarded by bus.mu
+}
+
+// Close unsubscribes; C is closed afterwards
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.removeLocked(s, nil)
+}
+
+// Err returns why C was closed by the bus (ErrSlowConsumer, ErrClosed), or nil
+func (s *Subscription) Err() error {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	return s.err
+}
@@ -0,0 +1,34 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// DiskSpace checks that the volume holding path has at least
+// health.min_free_disk MB available (0 disables the check)
+func DiskSpace(path string) Check {
+	return func(ctx context.Context) error {
+		minMB := global.cfg.Load().MinFreeDisk
+		if minMB == 0 {
+			return nil
+		}
+		dir, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		free, err := freeBytes(dir)
+		if errors.Is(err, errors.ErrUnsupported) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("stat %s: %w", dir, err)
+		}
+		if freeMB := free >> 20; freeMB < uint64(minMB) {
+			return fmt.Errorf("%s: %d MB free, need %d MB", dir, freeMB, minMB)
+		}
+		return nil
+	}
+}
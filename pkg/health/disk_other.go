@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !windows
+
+package health
+
+import "errors"
+
+// freeBytes is not implemented on this platform; the disk check passes
+func freeBytes(string) (uint64, error) {
+	return 0, errors.ErrUnsupported
+}
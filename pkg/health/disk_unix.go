@@ -0,0 +1,14 @@
+//go:build linux || darwin
+
+package health
+
+import "syscall"
+
+// freeBytes returns the space available to unprivileged users on the volume of dir
+func freeBytes(dir string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return 0, err
+	}
+	return st.Bavail * uint64(st.Bsize), nil
+}
@@ -0,0 +1,180 @@
+// Package health runs the named dependency checks behind the readiness probe.
+// Checks run in parallel, each with its own timeout, and results are cached
+// briefly so frequent probes do not hammer the dependencies.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-api-scaffold/pkg/config"
+)
+
+const (
+	StatusOK           = "ok"
+	StatusFailing      = "failing"
+	StatusShuttingDown = "shutting_down"
+)
+
+// Check returns an error when the dependency is unhealthy
+type Check func(ctx context.Context) error
+
+// Result is the outcome of one check
+type Result struct {
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs float64   `json:"duration_ms"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// Report is the outcome of all checks
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// OK reports whether the service is ready for traffic
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+var global = &registry{}
+
+func init() {
+	Configure(&config.DefaultConfig().Health)
+}
+
+type registry struct {
+	cfg          atomic.Pointer[config.HealthConfig]
+	shuttingDown atomic.Bool
+
+	mu     sync.Mutex
+	checks []*check
+}
+
+type check struct {
+	name string
+	fn   Check
+
+	mu   sync.Mutex // one run at a time; concurrent probes wait for its result
+	last Result
+}
+
+// Configure sets the timeouts, cache TTL and disk threshold (safe to call at runtime)
+func Configure(cfg *config.HealthConfig) {
+	c := *cfg
+	global.cfg.Store(&c)
+}
+
+// Register adds a named check; registering a name again replaces the check
+func Register(name string, fn Check) {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+	for _, c := range global.checks {
+		if c.name == name {
+			c.mu.Lock()
+			c.fn, c.last = fn, Result{}
+			c.mu.Unlock()
+			return
+		}
+	}
+	global.checks = append(global.checks, &check{name: name, fn: fn})
+}
+
+// SetShuttingDown makes readiness fail from now on, so load balancers
+// stop routing new requests before the servers stop
+func SetShuttingDown() {
+	global.shuttingDown.Store(true)
+}
+
+// ShutdownDelay is how long to keep serving after SetShuttingDown
+func ShutdownDelay() time.Duration {
+	return time.Duration(global.cfg.Load().ShutdownDelay) * time.Second
+}
+
+// Ready runs all checks (or reuses cached results) and reports readiness
+func Ready(ctx context.Context) Report {
+	global.mu.Lock()
+	checks := append([]*check(nil), global.checks...)
+	global.mu.Unlock()
+	cfg := global.cfg.Load()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, cfg)
+		}()
+	}
+	wg.Wait()
+
+	rep := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+	for i, c := range checks {
+		rep.Checks[c.name] = results[i]
+		if results[i].Status != StatusOK {
+			rep.Status = StatusFailing
+		}
+	}
+	if global.shuttingDown.Load() {
+		rep.Status = StatusShuttingDown
+	}
+	return rep
+}
+
+func (c *check) run(ctx context.Context, cfg *config.HealthConfig) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ttl := time.Duration(cfg.CacheTTL) * time.Millisecond
+	if !c.last.CheckedAt.IsZero() && time.Since(c.last.CheckedAt) < ttl {
+		return c.last
+	}
+
+	timeout := cfg.Timeout
+	if ms, ok := cfg.Timeouts[c.name]; ok {
+		timeout = ms
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := call(ctx, c.fn)
+	res := Result{
+		Status:     StatusOK,
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+		CheckedAt:  start,
+	}
+	if err != nil {
+		res.Status = StatusFailing
+		res.Error = err.Error()
+	}
+	// A cancelled probe says nothing about the dependency; don't cache it
+	if ctx.Err() != context.Canceled {
+		c.last = res
+	}
+	return res
+}
+
+// call runs fn but gives up at the deadline even if fn ignores ctx
+func call(ctx context.Context, fn Check) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		done <- fn(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out: %w", ctx.Err())
+	}
+}
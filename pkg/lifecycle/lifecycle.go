@@ -0,0 +1,180 @@
+// Package lifecycle starts the application's components in order and, on
+// SIGINT/SIGTERM or a component failure, stops them in reverse order, each
+// within its drain timeout and all within the shutdown deadline.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"go-api-scaffold/pkg/config"
+	"go-api-scaffold/pkg/health"
+	"go-api-scaffold/pkg/logger"
+)
+
+// ErrDeadline is returned by Run when components were still stopping at the shutdown deadline
+var ErrDeadline = errors.New("shutdown deadline exceeded")
+
+// finalTimeout bounds each Final hook that runs after the shutdown deadline
+const finalTimeout = 5 * time.Second
+
+// Hook is one component of the application (HTTP, gRPC, database, workers...).
+// Start must not block: long-running components start their own goroutines
+// and report a later failure with Manager.Fail.
+type Hook struct {
+	Name  string
+	Start func(ctx context.Context) error // optional
+	Stop  func(ctx context.Context) error // optional; ctx expires after the component's drain timeout
+	// Final hooks are quick cleanups (closing the database, flushing traces)
+	// that still run once the shutdown deadline has passed, each within finalTimeout
+	Final bool
+}
+
+// Manager runs the hooks of the application
+type Manager struct {
+	cfg     atomic.Pointer[config.ShutdownConfig]
+	hooks   []Hook
+	started int
+	failed  chan error
+}
+
+// New creates a manager with the shutdown timeouts
+func New(cfg *config.ShutdownConfig) *Manager {
+	m := &Manager{failed: make(chan error, 1)}
+	m.Configure(cfg)
+	return m
+}
+
+// Configure replaces the shutdown timeouts (safe to call at runtime)
+func (m *Manager) Configure(cfg *config.ShutdownConfig) {
+	c := *cfg
+	m.cfg.Store(&c)
+}
+
+// Append adds a component; components start in the order appended and stop in reverse
+func (m *Manager) Append(h Hook) {
+	m.hooks = append(m.hooks, h)
+}
+
+// Fail reports that a running component failed, which shuts the application down.
+// Only the first failure is kept.
+func (m *Manager) Fail(err error) {
+	select {
+	case m.failed <- err:
+	default:
+	}
+}
+
+// Run starts all components, waits for a signal, ctx cancellation or a
+// failure, then shuts down. It returns the failure and any stop errors.
+func (m *Manager) Run(ctx context.Context) error {
+	if err := m.start(ctx); err != nil {
+		return errors.Join(err, m.shutdown())
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var failure error
+	select {
+	case <-ctx.Done():
+		logger.Info("shutdown requested, stopping...")
+	case failure = <-m.failed:
+		logger.Errorf("shutting down after failure: %v", failure)
+	}
+	// Restore default signal handling: a second signal kills the process
+	stop()
+
+	// Fail readiness first and keep serving while load balancers notice
+	health.SetShuttingDown()
+	if delay := health.ShutdownDelay(); delay > 0 && failure == nil {
+		logger.Infof("readiness failing, waiting %v before stopping", delay)
+		time.Sleep(delay)
+	}
+
+	return errors.Join(failure, m.shutdown())
+}
+
+// start runs the Start hooks in order, stopping at the first error
+func (m *Manager) start(ctx context.Context) error {
+	for _, h := range m.hooks {
+		if h.Start != nil {
+			if err := h.Start(ctx); err != nil {
+				return fmt.Errorf("start %s: %w", h.Name, err)
+			}
+		}
+		m.started++
+	}
+	return nil
+}
+
+// shutdown stops the started components in reverse order. Past the deadline
+// only Final hooks run, each with a context of its own.
+func (m *Manager) shutdown() error {
+	cfg := m.cfg.Load()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Timeout)*time.Second)
+	defer cancel()
+
+	var errs []error
+	var skipped []string
+	for i := m.started - 1; i >= 0; i-- {
+		h := m.hooks[i]
+		if h.Stop == nil {
+			continue
+		}
+		parent, timeout := ctx, drain(cfg, h.Name)
+		if ctx.Err() != nil {
+			if !h.Final {
+				skipped = append(skipped, h.Name)
+				continue
+			}
+			parent, timeout = context.Background(), min(timeout, finalTimeout)
+		}
+		if err := m.stop(parent, h, timeout); err != nil {
+			logger.Errorf("stop %s: %v", h.Name, err)
+			errs = append(errs, fmt.Errorf("stop %s: %w", h.Name, err))
+			continue
+		}
+		logger.Infof("%s stopped", h.Name)
+	}
+	m.started = 0
+	if len(skipped) > 0 {
+		logger.Errorf("shutdown deadline (%ds) exceeded, not stopped: %s", cfg.Timeout, strings.Join(skipped, ", "))
+		errs = append(errs, ErrDeadline)
+	}
+	return errors.Join(errs...)
+}
+
+// stop runs one Stop hook, abandoning it when it ignores its deadline
+func (m *Manager) stop(parent context.Context, h Hook, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- h.Stop(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		// Give the hook a moment to react to the expired context itself
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(time.Second):
+			return fmt.Errorf("did not stop within %v", timeout)
+		}
+	}
+}
+
+func drain(cfg *config.ShutdownConfig, name string) time.Duration {
+	if s, ok := cfg.Drains[name]; ok {
+		return time.Duration(s) * time.Second
+	}
+	return time.Duration(cfg.Drain) * time.Second
+}
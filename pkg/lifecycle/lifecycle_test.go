@@ -0,0 +1,52 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-api-scaffold/pkg/config"
+)
+
+func TestShutdownRunsFinalHooksPastDeadline(t *testing.T) {
+	m := New(&config.ShutdownConfig{Timeout: 1, Drain: 10})
+
+	var stopped []string
+	hook := func(name string, final bool, stop func(ctx context.Context) error) {
+		m.Append(Hook{Name: name, Final: final, Stop: func(ctx context.Context) error {
+			stopped = append(stopped, name)
+			return stop(ctx)
+		}})
+	}
+	noop := func(context.Context) error { return nil }
+	var finalDeadline time.Time
+	hook("database", true, func(ctx context.Context) error {
+		if err := ctx.Err(); err != nil {
+			t.Errorf("database got an expired context: %v", err)
+		}
+		finalDeadline, _ = ctx.Deadline()
+		return nil
+	})
+	hook("jobs", false, noop)
+	hook("http", false, func(ctx context.Context) error {
+		// Hangs until the global deadline
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err := m.start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	err := m.shutdown()
+	if !errors.Is(err, ErrDeadline) {
+		t.Errorf("got %v, want ErrDeadline", err)
+	}
+	if want := []string{"http", "database"}; !reflect.DeepEqual(stopped, want) {
+		t.Errorf("stopped %v, want %v", stopped, want)
+	}
+	if left := time.Until(finalDeadline); left <= 0 || left > finalTimeout {
+		t.Errorf("database had %v left, want at most %v", left, finalTimeout)
+	}
+}
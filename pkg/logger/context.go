@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type ctxKey struct{}
+
+// WithContext returns a copy of ctx carrying l
+func WithContext(ctx context.Context, l *zap.SugaredLogger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the request-scoped logger in ctx, or the global logger.
+// Use it as logger.FromContext(ctx).Infow("msg", "key", value).
+func FromContext(ctx context.Context) *zap.SugaredLogger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ctxKey{}).(*zap.SugaredLogger); ok {
+			return l
+		}
+	}
+	return base()
+}
+
+// With returns a copy of ctx whose logger carries the extra fields
+func With(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	return WithContext(ctx, FromContext(ctx).With(keysAndValues...))
+}
+
+// base is the global logger without the caller skip of the package-level helpers
+func base() *zap.SugaredLogger {
+	if baseLogger == nil {
+		return L()
+	}
+	return baseLogger
+}
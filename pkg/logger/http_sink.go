@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"go-api-scaffold/pkg/config"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	httpSinkMinBackoff = 500 * time.Millisecond
+	httpSinkMaxBackoff = 10 * time.Second
+	httpSinkSyncWait   = 5 * time.Second
+)
+
+// httpSink buffers JSON entries and POSTs them in batches as a JSON array.
+// Logging never blocks on the network: entries are dropped when the buffer is full.
+type httpSink struct {
+	url        string
+	headers    map[string]string
+	batchSize  int
+	interval   time.Duration
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	client     *http.Client
+
+	entries chan []byte
+	flush   chan chan struct{}
+	dropped atomic.Int64
+}
+
+func newHTTPSink(cfg *config.HTTPSinkConfig) *httpSink {
+	maxRetries := 3
+	if cfg.MaxRetries != nil {
+		maxRetries = *cfg.MaxRetries
+	}
+	s := &httpSink{
+		url:        cfg.URL,
+		headers:    cfg.Headers,
+		batchSize:  orDefault(cfg.BatchSize, 100),
+		interval:   time.Duration(orDefault(cfg.FlushInterval, 1000)) * time.Millisecond,
+		maxRetries: maxRetries,
+		minBackoff: httpSinkMinBackoff,
+		maxBackoff: httpSinkMaxBackoff,
+		client:     &http.Client{Timeout: time.Duration(orDefault(cfg.Timeout, 5)) * time.Second},
+		entries:    make(chan []byte, orDefault(cfg.BufferSize, 10000)),
+		flush:      make(chan chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *httpSink) Send(_ zapcore.Entry, line []byte) error {
+	select {
+	case s.entries <- line:
+	default:
+		s.dropped.Add(1)
+	}
+	return nil
+}
+
+// Sync sends the buffered entries, waiting at most httpSinkSyncWait
+func (s *httpSink) Sync() error {
+	done := make(chan struct{})
+	select {
+	case s.flush <- done:
+	case <-time.After(httpSinkSyncWait):
+		return fmt.Errorf("http sink %s: flush timed out", s.url)
+	}
+	select {
+	case <-done:
+		return nil
+	case <-time.After(httpSinkSyncWait):
+		return fmt.Errorf("http sink %s: flush timed out", s.url)
+	}
+}
+
+func (s *httpSink) run() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, s.batchSize)
+	send := func() {
+		if len(batch) > 0 {
+			s.post(batch)
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case line := <-s.entries:
+			batch = append(batch, line)
+			if len(batch) >= s.batchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case done := <-s.flush:
+			// Drain what was queued before the flush request
+			for n := len(s.entries); n > 0; n-- {
+				batch = append(batch, <-s.entries)
+				if len(batch) >= s.batchSize {
+					send()
+				}
+			}
+			send()
+			close(done)
+		}
+	}
+}
+
+// post delivers one batch, retrying with exponential backoff
+func (s *httpSink) post(batch [][]byte) {
+	body := make([]byte, 0, 2+len(batch)*256)
+	body = append(body, '[')
+	body = append(body, bytes.Join(batch, []byte(","))...)
+	body = append(body, ']')
+
+	backoff := s.minBackoff
+	var err error
+	for attempt := 0; attempt <= s.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff = min(backoff*2, s.maxBackoff)
+		}
+		var retry bool
+		if retry, err = s.do(body); err == nil {
+			if n := s.dropped.Swap(0); n > 0 {
+				fmt.Fprintf(os.Stderr, "http sink %s: %d entries dropped (buffer full)\n", s.url, n)
+			}
+			return
+		}
+		if !retry {
+			break
+		}
+	}
+	fmt.Fprintf(os.Stderr, "http sink %s: dropping %d entries: %v\n", s.url, len(batch), err)
+}
+
+// do sends one request; retry reports whether a failure may be transient
+func (s *httpSink) do(body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		// Client errors other than timeouts and throttling will not succeed later
+		transient := resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests
+		return transient, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return false, nil
+}
+
+func orDefault(v, def int) int {
+	if v > 0 {
+		return v
+	}
+	return def
+}
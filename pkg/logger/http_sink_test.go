@@ -0,0 +1,249 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go-api-scaffold/pkg/config"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// sinkServer records the batches POSTed to it and answers with the next
+// status from statuses (200 once they run out)
+type sinkServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	batches  [][]json.RawMessage
+	attempts []time.Time
+	header   http.Header
+	received chan int
+}
+
+func newSinkServer(t *testing.T, statuses ...int) *sinkServer {
+	t.Helper()
+	srv := &sinkServer{statuses: statuses, received: make(chan int, 100)}
+	srv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("decode batch: %v", err)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+
+		srv.mu.Lock()
+		srv.attempts = append(srv.attempts, time.Now())
+		srv.header = r.Header.Clone()
+		code := http.StatusOK
+		if len(srv.statuses) > 0 {
+			code, srv.statuses = srv.statuses[0], srv.statuses[1:]
+		}
+		if code < 300 {
+			srv.batches = append(srv.batches, batch)
+		}
+		srv.mu.Unlock()
+
+		w.WriteHeader(code)
+		srv.received <- len(batch)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// waitBatch waits for the next request and returns its number of entries
+func (srv *sinkServer) waitBatch(t *testing.T) int {
+	t.Helper()
+	select {
+	case n := <-srv.received:
+		return n
+	case <-time.After(2 * time.Second):
+		t.Fatal("no batch received")
+		return 0
+	}
+}
+
+// snapshot returns the delivered batches and the times of all attempts
+func (srv *sinkServer) snapshot() ([][]json.RawMessage, []time.Time) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return srv.batches, srv.attempts
+}
+
+func newTestHTTPSink(cfg *config.HTTPSinkConfig) *httpSink {
+	s := newHTTPSink(cfg)
+	s.minBackoff = 20 * time.Millisecond
+	s.maxBackoff = time.Second
+	return s
+}
+
+func sendLines(s *httpSink, n int) {
+	for i := 0; i < n; i++ {
+		_ = s.Send(zapcore.Entry{}, []byte(fmt.Sprintf(`{"n":%d}`, i)))
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestHTTPSinkFlushesFullBatches(t *testing.T) {
+	srv := newSinkServer(t)
+	s := newTestHTTPSink(&config.HTTPSinkConfig{
+		URL: srv.URL, BatchSize: 3, FlushInterval: 3600000,
+		Headers: map[string]string{"Authorization": "Bearer sink"},
+	})
+
+	sendLines(s, 7)
+	for i := 0; i < 2; i++ {
+		if n := srv.waitBatch(t); n != 3 {
+			t.Fatalf("batch %d has %d entries, want 3", i, n)
+		}
+	}
+	// The 7th entry waits for the interval or a Sync
+	select {
+	case n := <-srv.received:
+		t.Fatalf("unexpected batch of %d entries", n)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err := s.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if n := srv.waitBatch(t); n != 1 {
+		t.Fatalf("flushed batch has %d entries, want 1", n)
+	}
+	batches, _ := srv.snapshot()
+	if got := string(batches[0][0]); got != `{"n":0}` {
+		t.Errorf("first entry = %s", got)
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if got := srv.header.Get("Authorization"); got != "Bearer sink" {
+		t.Errorf("Authorization = %q", got)
+	}
+}
+
+func TestHTTPSinkFlushesOnInterval(t *testing.T) {
+	srv := newSinkServer(t)
+	s := newTestHTTPSink(&config.HTTPSinkConfig{URL: srv.URL, BatchSize: 100, FlushInterval: 50})
+
+	sendLines(s, 2)
+	if n := srv.waitBatch(t); n != 2 {
+		t.Fatalf("batch has %d entries, want 2", n)
+	}
+}
+
+func TestHTTPSinkRetriesServerErrors(t *testing.T) {
+	srv := newSinkServer(t, http.StatusInternalServerError, http.StatusServiceUnavailable)
+	s := newTestHTTPSink(&config.HTTPSinkConfig{URL: srv.URL, MaxRetries: intPtr(3)})
+
+	sendLines(s, 1)
+	if err := s.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	batches, attempts := srv.snapshot()
+	if len(attempts) != 3 {
+		t.Fatalf("attempts = %d, want 3", len(attempts))
+	}
+	if len(batches) != 1 {
+		t.Fatalf("delivered %d batches, want 1", len(batches))
+	}
+	// Backoff doubles from minBackoff
+	if gap := attempts[1].Sub(attempts[0]); gap < s.minBackoff {
+		t.Errorf("first retry after %v, want >= %v", gap, s.minBackoff)
+	}
+	if gap := attempts[2].Sub(attempts[1]); gap < 2*s.minBackoff {
+		t.Errorf("second retry after %v, want >= %v", gap, 2*s.minBackoff)
+	}
+}
+
+func TestHTTPSinkRetryLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries *int
+		statuses   []int
+		want       int
+	}{
+		{"default", nil, []int{500, 500, 500, 500, 500}, 4},
+		{"max_retries 0", intPtr(0), []int{500, 500}, 1},
+		{"client error", intPtr(3), []int{400, 400}, 1},
+		{"throttled", intPtr(1), []int{429, 429}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newSinkServer(t, tt.statuses...)
+			s := newTestHTTPSink(&config.HTTPSinkConfig{URL: srv.URL, MaxRetries: tt.maxRetries})
+
+			sendLines(s, 1)
+			if err := s.Sync(); err != nil {
+				t.Fatal(err)
+			}
+			if _, attempts := srv.snapshot(); len(attempts) != tt.want {
+				t.Errorf("attempts = %d, want %d", len(attempts), tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPSinkDropsWhenBufferFull(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	var mu sync.Mutex
+	var delivered int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []json.RawMessage
+		_ = json.NewDecoder(r.Body).Decode(&batch)
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		mu.Lock()
+		delivered += len(batch)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+	s := newTestHTTPSink(&config.HTTPSinkConfig{URL: srv.URL, BatchSize: 1, BufferSize: 2})
+
+	// The first entry is in flight and blocks the sender; two more fit in
+	// the buffer and the rest are dropped without blocking the caller
+	sendLines(s, 1)
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no request received")
+	}
+	done := make(chan struct{})
+	go func() {
+		sendLines(s, 5)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Send blocked on a full buffer")
+	}
+	if got := s.dropped.Load(); got != 3 {
+		t.Errorf("dropped = %d, want 3", got)
+	}
+
+	close(release)
+	if err := s.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if delivered != 3 {
+		t.Errorf("delivered %d entries, want 3", delivered)
+	}
+	// The drop count is reported once a batch gets through
+	if got := s.dropped.Load(); got != 0 {
+		t.Errorf("dropped = %d after a delivery, want 0", got)
+	}
+}
@@ -0,0 +1,174 @@
+package logger
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"go-api-scaffold/pkg/config"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// levels holds the runtime-adjustable log levels shared by all loggers built by New
+var levels = &levelState{
+	global:   zap.NewAtomicLevel(),
+	min:      zap.NewAtomicLevel(),
+	packages: make(map[string]zapcore.Level),
+}
+
+type levelState struct {
+	mu       sync.RWMutex
+	def      zapcore.Level // configured level, restored by ResetLevel
+	global   zap.AtomicLevel
+	min      zap.AtomicLevel // lowest of global and package levels; cores are enabled from here
+	packages map[string]zapcore.Level
+}
+
+// Level returns the current global level
+func Level() string {
+	return levels.global.Level().String()
+}
+
+// SetLevel changes the global level at runtime
+func SetLevel(level string) error {
+	l, err := parseLevelStrict(level)
+	if err != nil {
+		return err
+	}
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	levels.global.SetLevel(l)
+	levels.updateMin()
+	return nil
+}
+
+// ResetLevel restores the level from the configuration
+func ResetLevel() {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	levels.global.SetLevel(levels.def)
+	levels.updateMin()
+}
+
+// DefaultLevel returns the configured level
+func DefaultLevel() string {
+	levels.mu.RLock()
+	defer levels.mu.RUnlock()
+	return levels.def.String()
+}
+
+// SetPackageLevel overrides the level for one package (the caller's directory,
+// e.g. "store", or a named logger such as "access"); an empty level removes it.
+func SetPackageLevel(pkg, level string) error {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	if level == "" {
+		delete(levels.packages, pkg)
+		levels.updateMin()
+		return nil
+	}
+	l, err := parseLevelStrict(level)
+	if err != nil {
+		return err
+	}
+	levels.packages[pkg] = l
+	levels.updateMin()
+	return nil
+}
+
+// PackageLevels returns the per-package overrides
+func PackageLevels() map[string]string {
+	levels.mu.RLock()
+	defer levels.mu.RUnlock()
+	out := make(map[string]string, len(levels.packages))
+	for pkg, l := range levels.packages {
+		out[pkg] = l.String()
+	}
+	return out
+}
+
+// Configure applies log.level and log.packages, e.g. after a config reload.
+// Runtime changes made with SetLevel and SetPackageLevel are replaced.
+func Configure(cfg *config.LogConfig) {
+	levels.configure(cfg.Level, cfg.Packages)
+}
+
+// configure applies the configured levels, replacing runtime changes
+func (s *levelState) configure(level string, packages map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.def = parseLevel(level)
+	s.global.SetLevel(s.def)
+	s.packages = make(map[string]zapcore.Level, len(packages))
+	for pkg, l := range packages {
+		s.packages[pkg] = parseLevel(l)
+	}
+	s.updateMin()
+}
+
+// updateMin must be called with mu held
+func (s *levelState) updateMin() {
+	lowest := s.global.Level()
+	for _, l := range s.packages {
+		if l < lowest {
+			lowest = l
+		}
+	}
+	s.min.SetLevel(lowest)
+}
+
+// levelFor returns the effective level of an entry; ok is false without overrides
+func (s *levelState) levelFor(ent zapcore.Entry) (zapcore.Level, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.packages) == 0 {
+		return s.global.Level(), false
+	}
+	if l, ok := s.packages[ent.LoggerName]; ok && ent.LoggerName != "" {
+		return l, true
+	}
+	if ent.Caller.Defined {
+		if l, ok := s.packages[filepath.Base(filepath.Dir(ent.Caller.File))]; ok {
+			return l, true
+		}
+	}
+	return s.global.Level(), true
+}
+
+// levelCore applies package overrides. The caller is only known when an entry
+// is written, so entries pass Check at the lowest level and are filtered in Write.
+type levelCore struct {
+	zapcore.Core
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields)}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	if l, overrides := levels.levelFor(ent); !overrides && ent.Level < l {
+		return ce
+	}
+	return ce.AddCore(ent, c)
+}
+
+func (c *levelCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if l, overrides := levels.levelFor(ent); overrides && ent.Level < l {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
+
+func parseLevelStrict(level string) (zapcore.Level, error) {
+	switch level {
+	case "debug", "info", "warn", "error":
+		return parseLevel(level), nil
+	default:
+		return zapcore.InfoLevel, fmt.Errorf("unsupported log level: %s (debug, info, warn, error)", level)
+	}
+}
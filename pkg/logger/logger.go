@@ -10,21 +10,35 @@ import (
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-var globalLogger *zap.SugaredLogger
+var (
+	globalLogger *zap.SugaredLogger
+	baseLogger   *zap.SugaredLogger // same outputs, callers reported at the call site of FromContext(ctx).X
+)
 
 // Init initializes the global logger
 func Init(cfg *config.LogConfig) error {
+	Configure(cfg)
 	l, err := New(cfg)
 	if err != nil {
 		return err
 	}
 	globalLogger = l
+	baseLogger = l.Desugar().WithOptions(zap.AddCallerSkip(-1)).Sugar()
 	return nil
 }
 
-// New creates a new logger instance
+// InitCLI points the global logger at stderr with warnings and errors only,
+// so CLI subcommands keep stdout for their own output
+func InitCLI() {
+	core := zapcore.NewCore(zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig()), zapcore.Lock(os.Stderr), zapcore.WarnLevel)
+	globalLogger = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1)).Sugar()
+	baseLogger = zap.New(core, zap.AddCaller()).Sugar()
+}
+
+// New creates a new logger instance.
+// Levels are shared and adjustable at runtime (see SetLevel); Init applies cfg.Level.
 func New(cfg *config.LogConfig) (*zap.SugaredLogger, error) {
-	level := parseLevel(cfg.Level)
+	level := levels.min
 
 	// Encoder config
 	encoderConfig := zapcore.EncoderConfig{
@@ -45,13 +59,13 @@ func New(cfg *config.LogConfig) (*zap.SugaredLogger, error) {
 
 	// Console output
 	if cfg.Output == "console" || cfg.Output == "both" {
-		consoleEncoder := zapcore.NewConsoleEncoder(encoderConfig)
+		consoleEncoder := newEncoder(cfg.Format, encoderConfig)
 		cores = append(cores, zapcore.NewCore(consoleEncoder, zapcore.AddSync(os.Stdout), level))
 	}
 
 	// File output
 	if (cfg.Output == "file" || cfg.Output == "both") && cfg.FilePath != "" {
-		fileEncoder := zapcore.NewJSONEncoder(encoderConfig)
+		fileEncoder := newEncoder(cfg.FileFormat, encoderConfig)
 		fileWriter := zapcore.AddSync(&lumberjack.Logger{
 			Filename:   cfg.FilePath,
 			MaxSize:    cfg.MaxSize,
@@ -64,16 +78,53 @@ func New(cfg *config.LogConfig) (*zap.SugaredLogger, error) {
 
 	if len(cores) == 0 {
 		// Default to console output
-		consoleEncoder := zapcore.NewConsoleEncoder(encoderConfig)
+		consoleEncoder := newEncoder(cfg.Format, encoderConfig)
 		cores = append(cores, zapcore.NewCore(consoleEncoder, zapcore.AddSync(os.Stdout), level))
 	}
 
-	core := zapcore.NewTee(cores...)
+	// Shipping sinks (syslog, http)
+	for i := range cfg.Sinks {
+		s, err := newSink(&cfg.Sinks[i])
+		if err != nil {
+			return nil, err
+		}
+		cores = append(cores, newSinkCore(newEncoder(cfg.Sinks[i].Format, encoderConfig), s, level))
+	}
+
+	redacted, err := withRedaction(zapcore.NewTee(cores...), &cfg.Redact)
+	if err != nil {
+		return nil, err
+	}
+	core := &levelCore{Core: redacted}
 	zapLogger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
 
 	return zapLogger.Sugar(), nil
 }
 
+// NewAccess creates the access logger: a separate JSON file when
+// log.access.file_path is set, otherwise the global logger's outputs
+func NewAccess(cfg *config.LogConfig) (*zap.Logger, error) {
+	if cfg.Access.FilePath == "" {
+		return L().Desugar().WithOptions(zap.WithCaller(false)).Named("access"), nil
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "time"
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	writer := zapcore.AddSync(&lumberjack.Logger{
+		Filename:   cfg.Access.FilePath,
+		MaxSize:    cfg.MaxSize,
+		MaxBackups: cfg.MaxBackups,
+		MaxAge:     cfg.MaxAge,
+		Compress:   cfg.Compress,
+	})
+	core, err := withRedaction(zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), writer, zapcore.DebugLevel), &cfg.Redact)
+	if err != nil {
+		return nil, err
+	}
+	return zap.New(core), nil
+}
+
 // L returns the global logger
 func L() *zap.SugaredLogger {
 	if globalLogger == nil {
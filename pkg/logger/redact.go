@@ -0,0 +1,316 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-api-scaffold/pkg/config"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// builtinPatterns are the named patterns accepted in log.redact.patterns;
+// any other entry is compiled as a regular expression
+var builtinPatterns = map[string]string{
+	"jwt":    `eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`,
+	"bearer": `(?i)\b(?:bearer|basic)\s+[A-Za-z0-9._~+/-]+=*`,
+	"email":  `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,
+	"card":   `\b\d(?:[ -]?\d){12,18}\b`,
+}
+
+// redactor masks sensitive field values and string patterns
+type redactor struct {
+	mask     string
+	fields   []string // normalized names, matched as substrings of field keys
+	keyValue *regexp.Regexp
+	patterns []*regexp.Regexp
+	card     *regexp.Regexp // replaced only when the digits look like a card number (isCardNumber)
+}
+
+func newRedactor(cfg *config.RedactConfig) (*redactor, error) {
+	r := &redactor{mask: cfg.Mask}
+	if r.mask == "" {
+		r.mask = "***"
+	}
+
+	names := make([]string, 0, len(cfg.Fields))
+	for _, f := range cfg.Fields {
+		r.fields = append(r.fields, normalizeKey(f))
+		names = append(names, regexp.QuoteMeta(f))
+	}
+	if len(names) > 0 {
+		// name=value and name: value inside messages, query strings and headers
+		r.keyValue = regexp.MustCompile(`(?i)((?:` + strings.Join(names, "|") + `)[\w-]*"?\s*[=:]\s*"?)(?:(?:bearer|basic)\s+)?[^\s&",;]+`)
+	}
+
+	for _, p := range cfg.Patterns {
+		expr, builtin := builtinPatterns[p]
+		if !builtin {
+			expr = p
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", p, err)
+		}
+		if p == "card" {
+			r.card = re
+			continue
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// sensitiveKey reports whether a field name matches a configured name
+func (r *redactor) sensitiveKey(key string) bool {
+	key = normalizeKey(key)
+	for _, f := range r.fields {
+		if strings.Contains(key, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactString masks patterns inside s
+func (r *redactor) redactString(s string) string {
+	for _, re := range r.patterns {
+		s = re.ReplaceAllString(s, r.mask)
+	}
+	if r.card != nil {
+		s = r.card.ReplaceAllStringFunc(s, func(m string) string {
+			if isCardNumber(m) {
+				return r.mask
+			}
+			return m
+		})
+	}
+	if r.keyValue != nil {
+		s = r.keyValue.ReplaceAllString(s, "${1}"+r.mask)
+	}
+	return s
+}
+
+// redactFields returns fields with sensitive values masked
+func (r *redactor) redactFields(fields []zapcore.Field) []zapcore.Field {
+	out := make([]zapcore.Field, 0, len(fields))
+	for _, f := range fields {
+		out = append(out, r.redactField(f)...)
+	}
+	return out
+}
+
+func (r *redactor) redactField(f zapcore.Field) []zapcore.Field {
+	if f.Type == zapcore.SkipType || f.Type == zapcore.NamespaceType {
+		return []zapcore.Field{f}
+	}
+	if r.sensitiveKey(f.Key) {
+		return []zapcore.Field{zap.String(f.Key, r.mask)}
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		f.String = r.redactString(f.String)
+		return []zapcore.Field{f}
+	case zapcore.BoolType, zapcore.DurationType, zapcore.TimeType, zapcore.TimeFullType,
+		zapcore.Float64Type, zapcore.Float32Type, zapcore.Complex128Type, zapcore.Complex64Type,
+		zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type,
+		zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type, zapcore.UintptrType:
+		return []zapcore.Field{f}
+	}
+
+	// Errors, stringers, maps, structs, ...: encode, then walk the result
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	keys := make([]string, 0, len(enc.Fields))
+	for k := range enc.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]zapcore.Field, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, zap.Any(k, r.redactValue(k, enc.Fields[k])))
+	}
+	return out
+}
+
+func (r *redactor) redactValue(key string, v interface{}) interface{} {
+	if key != "" && r.sensitiveKey(key) {
+		return r.mask
+	}
+	switch val := v.(type) {
+	case string:
+		return r.redactString(val)
+	case []byte:
+		return r.redactString(string(val))
+	case error:
+		return r.redactString(val.Error())
+	case fmt.Stringer:
+		return r.redactString(val.String())
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			m[k] = r.redactValue(k, item)
+		}
+		return m
+	case map[string]string:
+		m := make(map[string]string, len(val))
+		for k, item := range val {
+			m[k] = fmt.Sprint(r.redactValue(k, item))
+		}
+		return m
+	case map[string][]string: // http.Header, url.Values
+		m := make(map[string][]string, len(val))
+		for k, items := range val {
+			if r.sensitiveKey(k) {
+				m[k] = []string{r.mask}
+				continue
+			}
+			red := make([]string, len(items))
+			for i, item := range items {
+				red[i] = r.redactString(item)
+			}
+			m[k] = red
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(val))
+		for i, item := range val {
+			s[i] = r.redactValue("", item)
+		}
+		return s
+	default:
+		// Structs and other reflected values: walk their JSON form
+		b, err := json.Marshal(v)
+		if err != nil {
+			return v
+		}
+		var generic interface{}
+		if err := json.Unmarshal(b, &generic); err != nil {
+			return v
+		}
+		if s, ok := generic.(string); ok {
+			return r.redactString(s)
+		}
+		if _, ok := generic.(map[string]interface{}); ok {
+			return r.redactValue("", generic)
+		}
+		if _, ok := generic.([]interface{}); ok {
+			return r.redactValue("", generic)
+		}
+		return v
+	}
+}
+
+// redactCore masks sensitive data before entries reach the encoders
+type redactCore struct {
+	zapcore.Core
+	r *redactor
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.r.redactFields(fields)), r: c.r}
+}
+
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = c.r.redactString(ent.Message)
+	return c.Core.Write(ent, c.r.redactFields(fields))
+}
+
+// withRedaction wraps core when log.redact is enabled
+func withRedaction(core zapcore.Core, cfg *config.RedactConfig) (zapcore.Core, error) {
+	if !cfg.Enabled {
+		return core, nil
+	}
+	r, err := newRedactor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &redactCore{Core: core, r: r}, nil
+}
+
+func normalizeKey(key string) string {
+	return strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(key))
+}
+
+// cardNetworks are the issuer prefixes and number lengths of the major card
+// networks (Visa, Mastercard, Amex, Discover, JCB, Diners Club, UnionPay)
+var cardNetworks = []struct {
+	from, to       int // prefix range, compared on the first len(from) digits
+	minLen, maxLen int
+}{
+	{4, 4, 13, 13},
+	{4, 4, 16, 16},
+	{4, 4, 19, 19},
+	{51, 55, 16, 16},
+	{2221, 2720, 16, 16},
+	{34, 34, 15, 15},
+	{37, 37, 15, 15},
+	{6011, 6011, 16, 19},
+	{644, 649, 16, 19},
+	{65, 65, 16, 19},
+	{3528, 3589, 16, 19},
+	{300, 305, 14, 19},
+	{36, 36, 14, 19},
+	{38, 39, 16, 19},
+	{62, 62, 16, 19},
+}
+
+// isCardNumber reports whether a card-like digit sequence has the prefix and
+// length of a card network and passes the Luhn check. The Luhn check alone
+// would also mask one in ten ordinary IDs and millisecond timestamps.
+func isCardNumber(s string) bool {
+	digits := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			digits = append(digits, s[i])
+		}
+	}
+	if !issued(digits) {
+		return false
+	}
+	return luhn(digits)
+}
+
+func issued(digits []byte) bool {
+	for _, n := range cardNetworks {
+		if len(digits) < n.minLen || len(digits) > n.maxLen {
+			continue
+		}
+		width := len(strconv.Itoa(n.from))
+		prefix, _ := strconv.Atoi(string(digits[:width]))
+		if prefix >= n.from && prefix <= n.to {
+			return true
+		}
+	}
+	return false
+}
+
+// luhn validates the check digit of a card number
+func luhn(digits []byte) bool {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,25 @@
+//go:build !windows
+
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// WatchSignals switches to debug on SIGUSR1 and restores the configured level on SIGUSR2
+func WatchSignals() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR1, syscall.SIGUSR2)
+	go func() {
+		for sig := range ch {
+			if sig == syscall.SIGUSR1 {
+				_ = SetLevel("debug")
+			} else {
+				ResetLevel()
+			}
+			Infof("log level changed to %s (signal %v)", Level(), sig)
+		}
+	}()
+}
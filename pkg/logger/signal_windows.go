@@ -0,0 +1,6 @@
+//go:build windows
+
+package logger
+
+// WatchSignals is a no-op: SIGUSR1/SIGUSR2 do not exist on Windows, use the admin endpoint
+func WatchSignals() {}
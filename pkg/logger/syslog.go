@@ -0,0 +1,198 @@
+package logger
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go-api-scaffold/pkg/config"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	syslogDialTimeout  = 3 * time.Second
+	syslogWriteTimeout = 3 * time.Second
+	syslogRetryBackoff = 5 * time.Second
+	syslogBufferSize   = 10000
+	syslogSyncWait     = 5 * time.Second
+)
+
+// syslogSink writes RFC 5424 messages over UDP, TCP or a unix socket.
+// Stream connections use octet-counting framing (RFC 6587).
+// Logging never blocks on the destination: messages are queued and written
+// by a background goroutine, and dropped when the queue is full.
+type syslogSink struct {
+	network  string
+	address  string
+	facility int
+	hostname string
+	appName  string
+	procID   string
+
+	streaming    bool
+	writeTimeout time.Duration
+	retryBackoff time.Duration
+
+	messages chan []byte
+	flush    chan chan struct{}
+	dropped  atomic.Int64 // messages lost while the destination was unreachable or slow
+
+	// owned by run
+	conn    net.Conn
+	retryAt time.Time // no reconnect attempts before this
+}
+
+func newSyslogSink(cfg *config.SyslogSinkConfig) (*syslogSink, error) {
+	facility := "user"
+	if cfg.Facility != "" {
+		facility = cfg.Facility
+	}
+	code, ok := config.SyslogFacilities[facility]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility: %s", facility)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	appName := cfg.AppName
+	if appName == "" {
+		appName = filepath.Base(os.Args[0])
+	}
+
+	s := &syslogSink{
+		network:      cfg.Network,
+		address:      cfg.Address,
+		facility:     code,
+		hostname:     hostname,
+		appName:      appName,
+		procID:       strconv.Itoa(os.Getpid()),
+		streaming:    cfg.Network == "tcp" || cfg.Network == "unix",
+		writeTimeout: syslogWriteTimeout,
+		retryBackoff: syslogRetryBackoff,
+		messages:     make(chan []byte, syslogBufferSize),
+		flush:        make(chan chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// Send formats and queues one message
+func (s *syslogSink) Send(ent zapcore.Entry, line []byte) error {
+	select {
+	case s.messages <- s.format(ent, line):
+	default:
+		s.dropped.Add(1)
+	}
+	return nil
+}
+
+// Sync writes the queued messages, waiting at most syslogSyncWait
+func (s *syslogSink) Sync() error {
+	done := make(chan struct{})
+	select {
+	case s.flush <- done:
+	case <-time.After(syslogSyncWait):
+		return fmt.Errorf("syslog sink %s: flush timed out", s.address)
+	}
+	select {
+	case <-done:
+		return nil
+	case <-time.After(syslogSyncWait):
+		return fmt.Errorf("syslog sink %s: flush timed out", s.address)
+	}
+}
+
+func (s *syslogSink) run() {
+	for {
+		select {
+		case msg := <-s.messages:
+			s.write(msg)
+		case done := <-s.flush:
+			// Write what was queued before the flush request
+			for n := len(s.messages); n > 0; n-- {
+				s.write(<-s.messages)
+			}
+			close(done)
+		}
+	}
+}
+
+// write sends one message, reconnecting once on a write error. While the
+// destination is unreachable or stalled, messages are dropped and counted.
+func (s *syslogSink) write(msg []byte) {
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			if time.Now().Before(s.retryAt) {
+				s.dropped.Add(1)
+				return
+			}
+			if err := s.connect(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				s.dropped.Add(1)
+				return
+			}
+		}
+		_ = s.conn.SetWriteDeadline(time.Now().Add(s.writeTimeout))
+		if _, err := s.conn.Write(msg); err == nil {
+			if n := s.dropped.Swap(0); n > 0 {
+				fmt.Fprintf(os.Stderr, "syslog sink %s: %d entries dropped\n", s.address, n)
+			}
+			return
+		}
+		// A partial write breaks the framing: always start a new connection
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	s.retryAt = time.Now().Add(s.retryBackoff)
+	s.dropped.Add(1)
+}
+
+func (s *syslogSink) connect() error {
+	conn, err := net.DialTimeout(s.network, s.address, syslogDialTimeout)
+	if err != nil {
+		s.retryAt = time.Now().Add(s.retryBackoff)
+		return fmt.Errorf("syslog sink %s: %w", s.address, err)
+	}
+	s.conn = conn
+	return nil
+}
+
+// format renders <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID - MSG
+func (s *syslogSink) format(ent zapcore.Entry, line []byte) []byte {
+	msgID := "-"
+	if ent.LoggerName != "" {
+		msgID = ent.LoggerName
+	}
+	header := fmt.Sprintf("<%d>1 %s %s %s %s %s - ",
+		s.facility*8+syslogSeverity(ent.Level),
+		ent.Time.Format("2006-01-02T15:04:05.000000Z07:00"),
+		s.hostname, s.appName, s.procID, msgID)
+
+	msg := append([]byte(header), line...)
+	if s.streaming {
+		msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	}
+	return msg
+}
+
+func syslogSeverity(l zapcore.Level) int {
+	switch l {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	default: // dpanic, panic, fatal
+		return 2
+	}
+}
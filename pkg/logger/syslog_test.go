@@ -0,0 +1,260 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-scaffold/pkg/config"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func newTestSyslogSink(t *testing.T, network, address string) *syslogSink {
+	t.Helper()
+	s, err := newSyslogSink(&config.SyslogSinkConfig{Network: network, Address: address, Facility: "local0", AppName: "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.writeTimeout = 100 * time.Millisecond
+	s.retryBackoff = 100 * time.Millisecond
+	return s
+}
+
+func testEntry(level zapcore.Level) zapcore.Entry {
+	return zapcore.Entry{Level: level, Time: time.Date(2026, 1, 2, 3, 4, 5, 6000, time.UTC), LoggerName: "store"}
+}
+
+// readFrames reads octet-counted messages (RFC 6587) from a stream connection
+func readFrames(t *testing.T, conn net.Conn, n int) []string {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	rd := bufio.NewReader(conn)
+	var msgs []string
+	for len(msgs) < n {
+		size, err := rd.ReadString(' ')
+		if err != nil {
+			t.Fatalf("read frame length: %v", err)
+		}
+		l, err := strconv.Atoi(strings.TrimSpace(size))
+		if err != nil {
+			t.Fatalf("invalid frame length %q", size)
+		}
+		msg := make([]byte, l)
+		if _, err := io.ReadFull(rd, msg); err != nil {
+			t.Fatalf("read frame: %v", err)
+		}
+		msgs = append(msgs, string(msg))
+	}
+	return msgs
+}
+
+func TestSyslogFormat(t *testing.T) {
+	s := newTestSyslogSink(t, "udp", "127.0.0.1:1")
+	msg := string(s.format(testEntry(zapcore.ErrorLevel), []byte(`{"msg":"boom"}`)))
+
+	// local0 (16) * 8 + error (3) = 131
+	want := regexp.MustCompile(`^<131>1 2026-01-02T03:04:05\.000006Z \S+ api \d+ store - \{"msg":"boom"\}$`)
+	if !want.MatchString(msg) {
+		t.Errorf("got %q", msg)
+	}
+
+	s.streaming = true
+	framed := string(s.format(testEntry(zapcore.ErrorLevel), []byte(`{"msg":"boom"}`)))
+	if want := strconv.Itoa(len(msg)) + " " + msg; framed != want {
+		t.Errorf("framed = %q, want %q", framed, want)
+	}
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	for level, want := range map[zapcore.Level]int{
+		zapcore.DebugLevel: 7, zapcore.InfoLevel: 6, zapcore.WarnLevel: 4,
+		zapcore.ErrorLevel: 3, zapcore.DPanicLevel: 2, zapcore.FatalLevel: 2,
+	} {
+		if got := syslogSeverity(level); got != want {
+			t.Errorf("%s: got %d, want %d", level, got, want)
+		}
+	}
+}
+
+func TestSyslogUnknownFacility(t *testing.T) {
+	if _, err := newSyslogSink(&config.SyslogSinkConfig{Network: "udp", Address: "127.0.0.1:514", Facility: "local9"}); err == nil {
+		t.Error("unknown facility accepted")
+	}
+}
+
+func TestSyslogUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	s := newTestSyslogSink(t, "udp", pc.LocalAddr().String())
+	for i := 0; i < 3; i++ {
+		_ = s.Send(testEntry(zapcore.InfoLevel), []byte(fmt.Sprintf("line %d", i)))
+	}
+	if err := s.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 4096)
+	_ = pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for i := 0; i < 3; i++ {
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := string(buf[:n])
+		if !strings.HasPrefix(msg, "<134>1 ") || !strings.HasSuffix(msg, fmt.Sprintf(" - line %d", i)) {
+			t.Errorf("datagram %d: %q (datagrams carry no framing)", i, msg)
+		}
+	}
+}
+
+func TestSyslogStream(t *testing.T) {
+	for _, network := range []string{"tcp", "unix"} {
+		t.Run(network, func(t *testing.T) {
+			address := "127.0.0.1:0"
+			if network == "unix" {
+				address = filepath.Join(t.TempDir(), "syslog.sock")
+			}
+			ln, err := net.Listen(network, address)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ln.Close()
+
+			s := newTestSyslogSink(t, network, ln.Addr().String())
+			for i := 0; i < 3; i++ {
+				_ = s.Send(testEntry(zapcore.WarnLevel), []byte(fmt.Sprintf("line %d\nwith a newline", i)))
+			}
+
+			conn, err := ln.Accept()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			for i, msg := range readFrames(t, conn, 3) {
+				if !strings.HasPrefix(msg, "<132>1 ") || !strings.HasSuffix(msg, fmt.Sprintf(" - line %d\nwith a newline", i)) {
+					t.Errorf("message %d: %q", i, msg)
+				}
+			}
+		})
+	}
+}
+
+func TestSyslogReconnects(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	s := newTestSyslogSink(t, "tcp", ln.Addr().String())
+	_ = s.Send(testEntry(zapcore.InfoLevel), []byte("first"))
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	readFrames(t, conn, 1)
+	_ = conn.Close()
+
+	// Writes to the closed connection fail once the peer's reset arrives;
+	// the sink then dials again
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if c, err := ln.Accept(); err == nil {
+			accepted <- c
+		}
+	}()
+	deadline := time.After(3 * time.Second)
+	for i := 0; ; i++ {
+		_ = s.Send(testEntry(zapcore.InfoLevel), []byte(fmt.Sprintf("after %d", i)))
+		select {
+		case c := <-accepted:
+			defer c.Close()
+			msg := readFrames(t, c, 1)[0]
+			if !strings.Contains(msg, " - after ") {
+				t.Errorf("unexpected message %q", msg)
+			}
+			return
+		case <-deadline:
+			t.Fatal("sink did not reconnect")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func TestSyslogUnreachableDoesNotBlock(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := ln.Addr().String()
+	_ = ln.Close() // nothing listens there anymore
+
+	s := newTestSyslogSink(t, "tcp", address)
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		if err := s.Send(testEntry(zapcore.InfoLevel), []byte("lost")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("took %v", d)
+	}
+	if n := s.dropped.Load(); n != 100 {
+		t.Errorf("dropped = %d, want 100", n)
+	}
+}
+
+func TestSyslogStalledPeerDoesNotBlock(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Accept one connection and never read from it; later dials fail, so
+	// the drop count is not reset by a successful write
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		c, err := ln.Accept()
+		_ = ln.Close()
+		if err == nil {
+			accepted <- c
+		}
+	}()
+	defer func() {
+		select {
+		case c := <-accepted:
+			_ = c.Close()
+		default:
+		}
+	}()
+
+	s := newTestSyslogSink(t, "tcp", ln.Addr().String())
+	line := []byte(strings.Repeat("x", 64<<10))
+	start := time.Now()
+	for i := 0; i < 500; i++ { // far more than the socket buffers hold
+		_ = s.Send(testEntry(zapcore.InfoLevel), line)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("Send blocked for %v", d)
+	}
+	if err := s.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if s.dropped.Load() == 0 {
+		t.Error("no entries dropped by a stalled peer")
+	}
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor records per-method metrics for unary calls
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observeGRPC(info.FullMethod, "unary", err, time.Since(start))
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor records per-method metrics for streaming calls
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observeGRPC(info.FullMethod, streamType(info), err, time.Since(start))
+		return err
+	}
+}
+
+func observeGRPC(fullMethod, typ string, err error, d time.Duration) {
+	if global == nil {
+		return
+	}
+	service, method := splitMethod(fullMethod)
+	global.grpcRequests.WithLabelValues(service, method, typ, status.Code(err).String()).Inc()
+	global.grpcDuration.WithLabelValues(service, method, typ).Observe(d.Seconds())
+}
+
+// splitMethod splits "/package.Service/Method"
+func splitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
+
+func streamType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return "bidi_stream"
+	case info.IsClientStream:
+		return "client_stream"
+	default:
+		return "server_stream"
+	}
+}
@@ -0,0 +1,160 @@
+// Package metrics exposes Prometheus metrics for HTTP, gRPC, background jobs, the database pool and the Go runtime.
+// All functions are no-ops until Init is called.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-api-scaffold/pkg/config"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var global *registry
+
+type registry struct {
+	reg       *prometheus.Registry
+	namespace string
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	httpInFlight prometheus.Gauge
+
+	grpcRequests *prometheus.CounterVec
+	grpcDuration *prometheus.HistogramVec
+
+	jobsProcessed *prometheus.CounterVec
+	jobDuration   *prometheus.HistogramVec
+}
+
+// Init creates the metrics registry
+func Init(cfg *config.MetricsConfig) {
+	ns := cfg.Namespace
+	buckets := cfg.Buckets
+	if len(buckets) == 0 {
+		buckets = prometheus.DefBuckets
+	}
+
+	r := &registry{
+		reg:       prometheus.NewRegistry(),
+		namespace: ns,
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "http_requests_total",
+			Help:      "Total HTTP requests by route template, method and status.",
+		}, []string{"route", "method", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: ns,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route template, method and status.",
+			Buckets:   buckets,
+		}, []string{"route", "method", "status"}),
+		httpInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "http_requests_in_flight",
+			Help:      "HTTP requests currently being served.",
+		}),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "grpc_server_handled_total",
+			Help:      "Total gRPC calls completed on the server by method and status code.",
+		}, []string{"grpc_service", "grpc_method", "grpc_type", "grpc_code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: ns,
+			Name:      "grpc_server_handling_seconds",
+			Help:      "gRPC call latency on the server by method.",
+			Buckets:   buckets,
+		}, []string{"grpc_service", "grpc_method", "grpc_type"}),
+		jobsProcessed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "jobs_processed_total",
+			Help:      "Background job runs by queue, type and outcome (succeeded, retried, dead, released).",
+		}, []string{"queue", "type", "outcome"}),
+		jobDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: ns,
+			Name:      "job_duration_seconds",
+			Help:      "Background job run time by queue and type.",
+			Buckets:   buckets,
+		}, []string{"queue", "type"}),
+	}
+
+	r.reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{Namespace: ns}),
+		r.httpRequests, r.httpDuration, r.httpInFlight,
+		r.grpcRequests, r.grpcDuration,
+		r.jobsProcessed, r.jobDuration,
+	)
+	global = r
+}
+
+// Enabled reports whether Init has been called
+func Enabled() bool {
+	return global != nil
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	if global == nil {
+		return http.NotFoundHandler()
+	}
+	return promhttp.HandlerFor(global.reg, promhttp.HandlerOpts{Registry: global.reg})
+}
+
+// SetBuildInfo publishes a constant build_info gauge
+func SetBuildInfo(version, commit, buildTime string) {
+	if global == nil {
+		return
+	}
+	global.reg.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: global.namespace,
+		Name:      "build_info",
+		Help:      "Build information; the value is always 1.",
+		ConstLabels: prometheus.Labels{
+			"version":    version,
+			"commit":     commit,
+			"build_time": buildTime,
+		},
+	}, func() float64 { return 1 }))
+}
+
+// RegisterDB exposes sql.DBStats connection pool metrics
+func RegisterDB(name string, db *sql.DB) {
+	if global == nil {
+		return
+	}
+	global.reg.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// HTTPStart marks a request as in flight; call the returned func when it completes
+func HTTPStart() func() {
+	if global == nil {
+		return func() {}
+	}
+	global.httpInFlight.Inc()
+	return global.httpInFlight.Dec
+}
+
+// ObserveHTTP records a completed HTTP request
+func ObserveHTTP(route, method string, status int, d time.Duration) {
+	if global == nil {
+		return
+	}
+	code := strconv.Itoa(status)
+	global.httpRequests.WithLabelValues(route, method, code).Inc()
+	global.httpDuration.WithLabelValues(route, method, code).Observe(d.Seconds())
+}
+
+// ObserveJob records a background job run
+func ObserveJob(queue, typ, outcome string, d time.Duration) {
+	if global == nil {
+		return
+	}
+	global.jobsProcessed.WithLabelValues(queue, typ, outcome).Inc()
+	global.jobDuration.WithLabelValues(queue, typ).Observe(d.Seconds())
+}
@@ -0,0 +1,139 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepEvery is the number of checks between sweeps of idle keys
+const sweepEvery = 1024
+
+// Memory is an in-process limiter; limits are per instance
+type Memory struct {
+	mu     sync.Mutex
+	now    func() time.Time
+	states map[string]*memoryState
+	calls  int
+}
+
+type memoryState struct {
+	// token bucket
+	tokens float64
+	last   time.Time
+	// sliding window
+	windowStart time.Time
+	curr, prev  int
+
+	expires time.Time
+}
+
+// NewMemory creates an in-memory limiter
+func NewMemory() *Memory {
+	return &Memory{
+		now:    time.Now,
+		states: make(map[string]*memoryState),
+	}
+}
+
+// Allow implements Limiter
+func (m *Memory) Allow(_ context.Context, key string, rule Rule) (Result, error) {
+	if err := rule.Validate(); err != nil {
+		return Result{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.calls++
+	if m.calls%sweepEvery == 0 {
+		m.sweep(now)
+	}
+
+	key = rule.Algorithm + ":" + key
+	st, ok := m.states[key]
+	if !ok {
+		st = &memoryState{}
+		m.states[key] = st
+	}
+
+	if rule.Algorithm == TokenBucket {
+		return st.tokenBucket(now, rule), nil
+	}
+	return st.slidingWindow(now, rule), nil
+}
+
+func (m *Memory) sweep(now time.Time) {
+	for k, st := range m.states {
+		if now.After(st.expires) {
+			delete(m.states, k)
+		}
+	}
+}
+
+func (st *memoryState) tokenBucket(now time.Time, rule Rule) Result {
+	capacity := float64(rule.Limit)
+	rate := capacity / float64(rule.Window) // tokens per nanosecond
+
+	if st.last.IsZero() {
+		st.tokens = capacity
+	} else {
+		st.tokens = math.Min(capacity, st.tokens+float64(now.Sub(st.last))*rate)
+	}
+	st.last = now
+	st.expires = now.Add(rule.Window)
+
+	res := Result{Limit: rule.Limit}
+	if st.tokens >= 1 {
+		st.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration(math.Ceil((1 - st.tokens) / rate))
+	}
+	res.Remaining = int(st.tokens)
+	res.Reset = time.Duration(math.Ceil((capacity - st.tokens) / rate))
+	return res
+}
+
+func (st *memoryState) slidingWindow(now time.Time, rule Rule) Result {
+	w := rule.Window
+	start := now.Truncate(w)
+
+	switch {
+	case st.windowStart.IsZero() || !start.Before(st.windowStart.Add(2*w)):
+		st.prev, st.curr = 0, 0
+	case !start.Before(st.windowStart.Add(w)):
+		st.prev, st.curr = st.curr, 0
+	}
+	st.windowStart = start
+	st.expires = start.Add(2 * w)
+
+	elapsed := now.Sub(start)
+	estimate := float64(st.prev)*float64(w-elapsed)/float64(w) + float64(st.curr)
+
+	res := Result{Limit: rule.Limit, Reset: w - elapsed}
+	if estimate+1 > float64(rule.Limit) {
+		res.RetryAfter = slidingRetryAfter(rule.Limit, st.curr, st.prev, w, elapsed)
+		return res
+	}
+
+	st.curr++
+	res.Allowed = true
+	res.Remaining = int(float64(rule.Limit) - estimate - 1)
+	return res
+}
+
+// slidingRetryAfter estimates when the weighted count drops enough for one more request
+func slidingRetryAfter(limit, curr, prev int, w, elapsed time.Duration) time.Duration {
+	if curr+1 > limit || prev == 0 {
+		return w - elapsed
+	}
+	// prev*(w-t)/w + curr + 1 <= limit  =>  t >= w*(1-(limit-curr-1)/prev)
+	t := time.Duration(float64(w) * (1 - float64(limit-curr-1)/float64(prev)))
+	if t <= elapsed {
+		return time.Millisecond
+	}
+	return t - elapsed
+}
@@ -0,0 +1,142 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newTestMemory returns a limiter whose clock only moves through advance
+func newTestMemory() (*Memory, func(time.Duration)) {
+	m := NewMemory()
+	now := time.Unix(1000, 0) // aligned to the test windows
+	m.now = func() time.Time { return now }
+	return m, func(d time.Duration) { now = now.Add(d) }
+}
+
+// near tolerates the float rounding of the token bucket
+func near(got, want time.Duration) bool {
+	d := got - want
+	return d > -time.Millisecond && d < time.Millisecond
+}
+
+type step struct {
+	advance   time.Duration
+	allowed   bool
+	remaining int
+	retry     time.Duration
+}
+
+func runSteps(t *testing.T, l Limiter, advance func(time.Duration), rule Rule, steps []step) {
+	t.Helper()
+	for i, s := range steps {
+		advance(s.advance)
+		res, err := l.Allow(context.Background(), "ip:1.2.3.4", rule)
+		if err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+		if res.Allowed != s.allowed || res.Remaining != s.remaining || !near(res.RetryAfter, s.retry) {
+			t.Errorf("step %d: got allowed=%v remaining=%d retry=%v, want allowed=%v remaining=%d retry=%v",
+				i, res.Allowed, res.Remaining, res.RetryAfter, s.allowed, s.remaining, s.retry)
+		}
+		if res.Limit != rule.Limit {
+			t.Errorf("step %d: limit = %d, want %d", i, res.Limit, rule.Limit)
+		}
+	}
+}
+
+// The algorithm tables are shared by the memory and the Redis backends
+
+var tokenBucketRule = Rule{Algorithm: TokenBucket, Limit: 3, Window: 3 * time.Second} // one token per second
+
+var tokenBucketSteps = []step{
+	{0, true, 2, 0},
+	{0, true, 1, 0},
+	{0, true, 0, 0},
+	{0, false, 0, time.Second},
+	{500 * time.Millisecond, false, 0, 500 * time.Millisecond},
+	{600 * time.Millisecond, true, 0, 0},
+	{time.Minute, true, 2, 0}, // refilled up to the capacity only
+}
+
+var slidingWindowRule = Rule{Algorithm: SlidingWindow, Limit: 2, Window: 10 * time.Second}
+
+var slidingWindowSteps = []step{
+	{0, true, 1, 0},
+	{0, true, 0, 0},
+	{0, false, 0, 10 * time.Second},
+	// Halfway through the next window the previous one still counts half: 2*0.5 + 0
+	{15 * time.Second, true, 0, 0},
+	{0, false, 0, 5 * time.Second},
+	// Two windows later nothing is left
+	{20 * time.Second, true, 1, 0},
+}
+
+func TestMemoryTokenBucket(t *testing.T) {
+	m, advance := newTestMemory()
+	runSteps(t, m, advance, tokenBucketRule, tokenBucketSteps)
+}
+
+func TestMemorySlidingWindow(t *testing.T) {
+	m, advance := newTestMemory()
+	runSteps(t, m, advance, slidingWindowRule, slidingWindowSteps)
+}
+
+func TestMemoryKeysAreIndependent(t *testing.T) {
+	m, _ := newTestMemory()
+	ctx := context.Background()
+	bucket := Rule{Algorithm: TokenBucket, Limit: 1, Window: time.Minute}
+	window := Rule{Algorithm: SlidingWindow, Limit: 1, Window: time.Minute}
+
+	for _, c := range []struct {
+		key  string
+		rule Rule
+	}{
+		{"ip:1.1.1.1", bucket},
+		{"ip:2.2.2.2", bucket},
+		{"ip:1.1.1.1", window}, // same key, other algorithm
+	} {
+		res, err := m.Allow(ctx, c.key, c.rule)
+		if err != nil || !res.Allowed {
+			t.Errorf("%s %s: allowed=%v err=%v, want allowed", c.rule.Algorithm, c.key, res.Allowed, err)
+		}
+	}
+	if res, _ := m.Allow(ctx, "ip:1.1.1.1", bucket); res.Allowed {
+		t.Error("second request of ip:1.1.1.1 allowed, want limited")
+	}
+}
+
+func TestMemorySweepsIdleKeys(t *testing.T) {
+	m, advance := newTestMemory()
+	ctx := context.Background()
+	rule := Rule{Algorithm: TokenBucket, Limit: 10000, Window: time.Second}
+
+	if _, err := m.Allow(ctx, "idle", rule); err != nil {
+		t.Fatal(err)
+	}
+	advance(2 * time.Second)
+	for i := 0; i < sweepEvery; i++ {
+		if _, err := m.Allow(ctx, "busy", rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := m.states[TokenBucket+":idle"]; ok {
+		t.Error("idle key not swept")
+	}
+	if _, ok := m.states[TokenBucket+":busy"]; !ok {
+		t.Error("busy key swept")
+	}
+}
+
+func TestMemoryRejectsInvalidRules(t *testing.T) {
+	m, _ := newTestMemory()
+	for _, rule := range []Rule{
+		{Algorithm: "leaky_bucket", Limit: 1, Window: time.Second},
+		{Algorithm: TokenBucket, Limit: 0, Window: time.Second},
+		{Algorithm: SlidingWindow, Limit: 1, Window: time.Microsecond},
+	} {
+		if _, err := m.Allow(context.Background(), "k", rule); err == nil {
+			t.Errorf("%+v: no error", rule)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Package ratelimit provides request rate limiting with pluggable backends.
+//
+// Two algorithms are supported:
+//   - token_bucket: bursts up to Limit, refilled continuously at Limit per Window
+//   - sliding_window: at most Limit per rolling Window (weighted two-window counter)
+//
+// Backends: NewMemory for a single instance, NewRedis for limits shared across
+// replicas through any Redis-compatible server.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Algorithms
+const (
+	TokenBucket   = "token_bucket"
+	SlidingWindow = "sliding_window"
+)
+
+// Rule is a limit applied per key
+type Rule struct {
+	Algorithm string
+	Limit     int
+	Window    time.Duration
+}
+
+// Validate checks the rule parameters
+func (r Rule) Validate() error {
+	if r.Algorithm != TokenBucket && r.Algorithm != SlidingWindow {
+		return fmt.Errorf("unsupported algorithm: %s", r.Algorithm)
+	}
+	if r.Limit < 1 {
+		return fmt.Errorf("limit must be positive")
+	}
+	if r.Window < time.Millisecond {
+		return fmt.Errorf("window must be at least 1ms")
+	}
+	return nil
+}
+
+// Result is the outcome of a single check
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // until the limit is fully replenished
+	RetryAfter time.Duration // set when not allowed
+}
+
+// Limiter checks and consumes one request for key under rule
+type Limiter interface {
+	Allow(ctx context.Context, key string, rule Rule) (Result, error)
+}
@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RedisClient is the single command the Redis backend needs. Any client
+// (go-redis, rueidis, ...) can be adapted to it; NewRESPClient is a minimal
+// built-in implementation for Redis-compatible servers with Lua scripting.
+type RedisClient interface {
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+// Redis is a limiter whose state lives in Redis, shared by all replicas.
+// Scripts measure time with the server clock so replicas with skewed clocks
+// agree; only the sliding window's current window is chosen by the caller,
+// as scripts may only touch the keys they are passed (Redis Cluster).
+type Redis struct {
+	client RedisClient
+	prefix string
+	now    func() time.Time
+}
+
+// NewRedis creates a Redis-backed limiter; keys are stored under prefix
+func NewRedis(client RedisClient, prefix string) *Redis {
+	return &Redis{client: client, prefix: prefix, now: time.Now}
+}
+
+// Allow implements Limiter
+func (r *Redis) Allow(ctx context.Context, key string, rule Rule) (Result, error) {
+	if err := rule.Validate(); err != nil {
+		return Result{}, err
+	}
+
+	// Hash tag keeps the keys of the sliding window's windows on one cluster slot
+	redisKey := "{" + r.prefix + rule.Algorithm + ":" + key + "}"
+	window := rule.Window.Milliseconds()
+
+	script, keys, args := tokenBucketScript, []string{redisKey}, []interface{}{rule.Limit, window}
+	if rule.Algorithm == SlidingWindow {
+		now := r.now().UnixMilli()
+		start := now - now%window
+		script = slidingWindowScript
+		keys = []string{fmt.Sprintf("%s:%d", redisKey, start), fmt.Sprintf("%s:%d", redisKey, start-window)}
+		args = append(args, start)
+	}
+
+	reply, err := r.client.Eval(ctx, script, keys, args...)
+	if err != nil {
+		return Result{}, err
+	}
+
+	vals, ok := reply.([]interface{})
+	if !ok || len(vals) != 4 {
+		return Result{}, fmt.Errorf("unexpected script reply: %v", reply)
+	}
+	nums := make([]int64, 4)
+	for i, v := range vals {
+		n, ok := v.(int64)
+		if !ok {
+			return Result{}, fmt.Errorf("unexpected script reply: %v", reply)
+		}
+		nums[i] = n
+	}
+
+	return Result{
+		Allowed:    nums[0] == 1,
+		Limit:      rule.Limit,
+		Remaining:  int(nums[1]),
+		RetryAfter: time.Duration(nums[2]) * time.Millisecond,
+		Reset:      time.Duration(nums[3]) * time.Millisecond,
+	}, nil
+}
+
+// Both scripts return {allowed, remaining, retry_after_ms, reset_ms}
+
+const redisNow = `
+if redis.replicate_commands then redis.replicate_commands() end
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+`
+
+const tokenBucketScript = redisNow + `
+local capacity = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local rate = capacity / window
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil then
+  tokens = capacity
+else
+  tokens = math.min(capacity, tokens + (now - ts) * rate)
+end
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry = math.ceil((1 - tokens) / rate)
+end
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], window)
+return {allowed, math.floor(tokens), retry, math.ceil((capacity - tokens) / rate)}
+`
+
+// KEYS[1] and KEYS[2] count the current and the previous window, ARGV[3] is
+// the start of the current window
+const slidingWindowScript = redisNow + `
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local start = tonumber(ARGV[3])
+local cur = tonumber(redis.call('GET', KEYS[1]) or '0')
+local prev = tonumber(redis.call('GET', KEYS[2]) or '0')
+local elapsed = math.min(math.max(now - start, 0), window - 1)
+local estimate = prev * (window - elapsed) / window + cur
+if estimate + 1 > limit then
+  local retry = window - elapsed
+  if cur + 1 <= limit and prev > 0 then
+    retry = math.max(1, math.ceil(window * (1 - (limit - cur - 1) / prev)) - elapsed)
+  end
+  return {0, 0, retry, window - elapsed}
+end
+redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], window * 2)
+return {1, math.floor(limit - estimate - 1), 0, window - elapsed}
+`
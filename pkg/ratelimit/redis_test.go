@@ -0,0 +1,336 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// fakeRedis is a local RESP stand-in for connection and protocol tests. It
+// answers AUTH, SELECT and EVAL; EVAL counts requests per first key against
+// ARGV[1] instead of running the script (see newTestRedis for the scripts).
+type fakeRedis struct {
+	ln       net.Listener
+	password string
+
+	mu       sync.Mutex
+	dials    int
+	cmds     [][]string
+	counts   map[string]int64
+	evalErr  string // error reply to EVAL when set
+	hangup   bool   // close the connection instead of replying
+	stall    bool   // never reply
+	badReply bool   // reply to EVAL with a string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeRedis{ln: ln, password: password, counts: make(map[string]int64)}
+	t.Cleanup(func() { _ = ln.Close() })
+	go f.serve()
+	return f
+}
+
+func (f *fakeRedis) client(password string, db int) *RESPClient {
+	c := NewRESPClient(f.ln.Addr().String(), password, db, 2)
+	c.timeout = 200 * time.Millisecond
+	return c
+}
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.ln.Accept()
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.dials++
+		f.mu.Unlock()
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	rd := bufio.NewReader(conn)
+	authed := f.password == ""
+	for {
+		req, err := readReply(rd)
+		if err != nil {
+			return
+		}
+		items, _ := req.([]interface{})
+		cmd := make([]string, len(items))
+		for i, it := range items {
+			cmd[i], _ = it.(string)
+		}
+
+		f.mu.Lock()
+		f.cmds = append(f.cmds, cmd)
+		var reply string
+		switch {
+		case f.hangup:
+			f.mu.Unlock()
+			return
+		case f.stall:
+			f.mu.Unlock()
+			time.Sleep(time.Second)
+			return
+		case strings.EqualFold(cmd[0], "AUTH"):
+			if cmd[1] != f.password {
+				reply = "-WRONGPASS invalid password\r\n"
+			} else {
+				authed = true
+				reply = "+OK\r\n"
+			}
+		case !authed:
+			reply = "-NOAUTH Authentication required.\r\n"
+		case strings.EqualFold(cmd[0], "SELECT"):
+			reply = "+OK\r\n"
+		case strings.EqualFold(cmd[0], "EVAL") && f.evalErr != "":
+			reply = "-" + f.evalErr + "\r\n"
+		case strings.EqualFold(cmd[0], "EVAL") && f.badReply:
+			reply = "+OK\r\n"
+		case strings.EqualFold(cmd[0], "EVAL"):
+			nkeys, _ := strconv.Atoi(cmd[2])
+			limit, _ := strconv.ParseInt(cmd[3+nkeys], 10, 64)
+			window, _ := strconv.ParseInt(cmd[4+nkeys], 10, 64)
+			f.counts[cmd[3]]++
+			n := f.counts[cmd[3]]
+			allowed, remaining, retry := int64(1), limit-n, int64(0)
+			if n > limit {
+				allowed, remaining, retry = 0, 0, window
+			}
+			reply = fmt.Sprintf("*4\r\n:%d\r\n:%d\r\n:%d\r\n:%d\r\n", allowed, remaining, retry, window)
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		f.mu.Unlock()
+
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) set(fn func(*fakeRedis)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fn(f)
+}
+
+func (f *fakeRedis) dialCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.dials
+}
+
+func TestRedisAllow(t *testing.T) {
+	f := newFakeRedis(t, "secret")
+	rl := NewRedis(f.client("secret", 2), "rl:")
+	rule := Rule{Algorithm: TokenBucket, Limit: 2, Window: time.Minute}
+
+	for i, want := range []Result{
+		{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Minute},
+		{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Minute},
+		{Allowed: false, Limit: 2, Remaining: 0, RetryAfter: time.Minute, Reset: time.Minute},
+	} {
+		got, err := rl.Allow(context.Background(), "ip:1.2.3.4", rule)
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		if got != want {
+			t.Errorf("request %d: got %+v, want %+v", i, got, want)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.dials != 1 {
+		t.Errorf("dials = %d, want 1 (pooled connection)", f.dials)
+	}
+	wantCmds := [][]string{
+		{"AUTH", "secret"},
+		{"SELECT", "2"},
+		{"EVAL", tokenBucketScript, "1", "{rl:token_bucket:ip:1.2.3.4}", "2", "60000"},
+	}
+	for i, want := range wantCmds {
+		if got := strings.Join(f.cmds[i], " "); got != strings.Join(want, " ") {
+			t.Errorf("command %d: got %.60q, want %.60q", i, got, strings.Join(want, " "))
+		}
+	}
+}
+
+func TestRedisSlidingWindowUsesItsScript(t *testing.T) {
+	f := newFakeRedis(t, "")
+	rl := NewRedis(f.client("", 0), "")
+	rl.now = func() time.Time { return time.UnixMilli(1_700_000_000_400) }
+	if _, err := rl.Allow(context.Background(), "user:7", Rule{Algorithm: SlidingWindow, Limit: 5, Window: time.Second}); err != nil {
+		t.Fatal(err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// No AUTH or SELECT without password and db. Both windows are declared
+	// keys, in the slot of the hash tag.
+	want := []string{"EVAL", slidingWindowScript, "2",
+		"{sliding_window:user:7}:1700000000000", "{sliding_window:user:7}:1699999999000", "5", "1000", "1700000000000"}
+	if got := f.cmds[0]; strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("got %.120q, want %.120q", strings.Join(got, " "), strings.Join(want, " "))
+	}
+}
+
+func TestRedisErrors(t *testing.T) {
+	rule := Rule{Algorithm: TokenBucket, Limit: 1, Window: time.Second}
+
+	t.Run("error reply keeps the connection", func(t *testing.T) {
+		f := newFakeRedis(t, "")
+		rl := NewRedis(f.client("", 0), "")
+		f.set(func(f *fakeRedis) { f.evalErr = "NOSCRIPT scripting disabled" })
+
+		_, err := rl.Allow(context.Background(), "k", rule)
+		var redisErr RedisError
+		if !errors.As(err, &redisErr) || string(redisErr) != "NOSCRIPT scripting disabled" {
+			t.Fatalf("err = %v, want the RedisError", err)
+		}
+		f.set(func(f *fakeRedis) { f.evalErr = "" })
+		if _, err := rl.Allow(context.Background(), "k", rule); err != nil {
+			t.Fatal(err)
+		}
+		if n := f.dialCount(); n != 1 {
+			t.Errorf("dials = %d, want 1", n)
+		}
+	})
+
+	t.Run("closed connection is redialed", func(t *testing.T) {
+		f := newFakeRedis(t, "")
+		rl := NewRedis(f.client("", 0), "")
+		f.set(func(f *fakeRedis) { f.hangup = true })
+		if _, err := rl.Allow(context.Background(), "k", rule); err == nil {
+			t.Fatal("no error from a closed connection")
+		}
+		f.set(func(f *fakeRedis) { f.hangup = false })
+		if _, err := rl.Allow(context.Background(), "k", rule); err != nil {
+			t.Fatal(err)
+		}
+		if n := f.dialCount(); n != 2 {
+			t.Errorf("dials = %d, want 2", n)
+		}
+	})
+
+	t.Run("stalled server times out", func(t *testing.T) {
+		f := newFakeRedis(t, "")
+		rl := NewRedis(f.client("", 0), "")
+		f.set(func(f *fakeRedis) { f.stall = true })
+		start := time.Now()
+		var netErr net.Error
+		if _, err := rl.Allow(context.Background(), "k", rule); !errors.As(err, &netErr) || !netErr.Timeout() {
+			t.Fatalf("err = %v, want a timeout", err)
+		}
+		if d := time.Since(start); d > 500*time.Millisecond {
+			t.Errorf("took %v, want the client timeout", d)
+		}
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		f := newFakeRedis(t, "secret")
+		rl := NewRedis(f.client("nope", 0), "")
+		if _, err := rl.Allow(context.Background(), "k", rule); err == nil || !strings.Contains(err.Error(), "redis auth") {
+			t.Fatalf("err = %v, want an auth error", err)
+		}
+	})
+
+	t.Run("unexpected reply", func(t *testing.T) {
+		f := newFakeRedis(t, "")
+		rl := NewRedis(f.client("", 0), "")
+		f.set(func(f *fakeRedis) { f.badReply = true })
+		if _, err := rl.Allow(context.Background(), "k", rule); err == nil || !strings.Contains(err.Error(), "unexpected script reply") {
+			t.Fatalf("err = %v, want an unexpected reply error", err)
+		}
+	})
+}
+
+// newTestRedis returns a limiter on a miniredis server, which runs the Lua
+// scripts. The server clock (TIME, expiry) and the limiter's clock only move
+// through advance.
+func newTestRedis(t *testing.T) (*Redis, func(time.Duration)) {
+	t.Helper()
+	m := miniredis.RunT(t)
+	now := time.Unix(1000, 0) // aligned to the test windows
+	m.SetTime(now)
+	rl := NewRedis(NewRESPClient(m.Addr(), "", 0, 2), "rl:")
+	rl.now = func() time.Time { return now }
+	return rl, func(d time.Duration) {
+		now = now.Add(d)
+		m.SetTime(now)
+		m.FastForward(d)
+	}
+}
+
+func TestRedisTokenBucketScript(t *testing.T) {
+	rl, advance := newTestRedis(t)
+	runSteps(t, rl, advance, tokenBucketRule, tokenBucketSteps)
+}
+
+func TestRedisSlidingWindowScript(t *testing.T) {
+	rl, advance := newTestRedis(t)
+	runSteps(t, rl, advance, slidingWindowRule, slidingWindowSteps)
+}
+
+func TestRedisScriptsKeepKeysApart(t *testing.T) {
+	rl, _ := newTestRedis(t)
+	ctx := context.Background()
+	bucket := Rule{Algorithm: TokenBucket, Limit: 1, Window: time.Minute}
+	window := Rule{Algorithm: SlidingWindow, Limit: 1, Window: time.Minute}
+
+	for _, c := range []struct {
+		key  string
+		rule Rule
+	}{
+		{"ip:1.1.1.1", bucket},
+		{"ip:2.2.2.2", bucket},
+		{"ip:1.1.1.1", window}, // same key, other algorithm
+		{"ip:2.2.2.2", window},
+	} {
+		res, err := rl.Allow(ctx, c.key, c.rule)
+		if err != nil || !res.Allowed {
+			t.Errorf("%s %s: allowed=%v err=%v, want allowed", c.rule.Algorithm, c.key, res.Allowed, err)
+		}
+	}
+	for _, rule := range []Rule{bucket, window} {
+		if res, err := rl.Allow(ctx, "ip:1.1.1.1", rule); err != nil || res.Allowed {
+			t.Errorf("%s: second request allowed=%v err=%v, want limited", rule.Algorithm, res.Allowed, err)
+		}
+	}
+}
+
+func TestRedisSlidingWindowCallerClockAhead(t *testing.T) {
+	rl, _ := newTestRedis(t)
+	ctx := context.Background()
+	rule := Rule{Algorithm: SlidingWindow, Limit: 1, Window: 10 * time.Second}
+
+	// A replica whose clock is already in the next window counts there, with
+	// the server's time clamped to that window
+	skewed := time.Unix(1012, 0)
+	rl.now = func() time.Time { return skewed }
+	res, err := rl.Allow(ctx, "k", rule)
+	if err != nil || !res.Allowed || res.Reset <= 0 || res.Reset > rule.Window {
+		t.Fatalf("got %+v, %v, want allowed with a reset within the window", res, err)
+	}
+	if res, _ := rl.Allow(ctx, "k", rule); res.Allowed {
+		t.Error("second request allowed, want limited")
+	}
+}
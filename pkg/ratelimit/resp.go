@@ -0,0 +1,206 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// RESPClient is a minimal Redis protocol (RESP2) client implementing RedisClient.
+// It keeps a small pool of connections and works with any Redis-compatible server
+// (Redis, Valkey, KeyDB, Dragonfly, or a local stand-in in tests).
+type RESPClient struct {
+	addr     string
+	password string
+	db       int
+	timeout  time.Duration
+	pool     chan *respConn
+}
+
+type respConn struct {
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+// RedisError is an error reply from the server
+type RedisError string
+
+func (e RedisError) Error() string { return string(e) }
+
+// NewRESPClient creates a client; connections are dialed lazily
+func NewRESPClient(addr, password string, db, poolSize int) *RESPClient {
+	if poolSize < 1 {
+		poolSize = 1
+	}
+	return &RESPClient{
+		addr:     addr,
+		password: password,
+		db:       db,
+		timeout:  time.Second,
+		pool:     make(chan *respConn, poolSize),
+	}
+}
+
+// Eval implements RedisClient
+func (c *RESPClient) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	cmd := make([]interface{}, 0, 3+len(keys)+len(args))
+	cmd = append(cmd, "EVAL", script, len(keys))
+	for _, k := range keys {
+		cmd = append(cmd, k)
+	}
+	cmd = append(cmd, args...)
+	return c.Do(ctx, cmd...)
+}
+
+// Do sends a command and returns its reply: string, int64, nil, []interface{} or RedisError
+func (c *RESPClient) Do(ctx context.Context, args ...interface{}) (interface{}, error) {
+	rc, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := rc.do(ctx, c.timeout, args...)
+	var redisErr RedisError
+	if err != nil && !errors.As(err, &redisErr) {
+		// Connection state unknown after an I/O error
+		_ = rc.conn.Close()
+		return nil, err
+	}
+	c.put(rc)
+	return reply, err
+}
+
+// Close closes idle connections
+func (c *RESPClient) Close() error {
+	for {
+		select {
+		case rc := <-c.pool:
+			_ = rc.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *RESPClient) get(ctx context.Context) (*respConn, error) {
+	select {
+	case rc := <-c.pool:
+		return rc, nil
+	default:
+	}
+
+	d := net.Dialer{Timeout: c.timeout}
+	conn, err := d.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, err
+	}
+	rc := &respConn{conn: conn, rd: bufio.NewReader(conn)}
+
+	if c.password != "" {
+		if _, err := rc.do(ctx, c.timeout, "AUTH", c.password); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("redis auth: %w", err)
+		}
+	}
+	if c.db != 0 {
+		if _, err := rc.do(ctx, c.timeout, "SELECT", c.db); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("redis select: %w", err)
+		}
+	}
+	return rc, nil
+}
+
+func (c *RESPClient) put(rc *respConn) {
+	select {
+	case c.pool <- rc:
+	default:
+		_ = rc.conn.Close()
+	}
+}
+
+func (rc *respConn) do(ctx context.Context, timeout time.Duration, args ...interface{}) (interface{}, error) {
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = rc.conn.SetDeadline(deadline)
+
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, a := range args {
+		var s string
+		switch v := a.(type) {
+		case string:
+			s = v
+		case []byte:
+			s = string(v)
+		case int:
+			s = strconv.Itoa(v)
+		case int64:
+			s = strconv.FormatInt(v, 10)
+		default:
+			s = fmt.Sprint(v)
+		}
+		buf = append(buf, "$"+strconv.Itoa(len(s))+"\r\n"+s+"\r\n"...)
+	}
+	if _, err := rc.conn.Write(buf); err != nil {
+		return nil, err
+	}
+	return readReply(rc.rd)
+}
+
+func readReply(rd *bufio.Reader) (interface{}, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 {
+		return nil, fmt.Errorf("redis: short reply %q", line)
+	}
+	payload := line[1 : len(line)-2]
+
+	switch line[0] {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, RedisError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, b); err != nil {
+			return nil, err
+		}
+		return string(b[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		vals := make([]interface{}, n)
+		for i := range vals {
+			// Nested error replies are returned as values
+			v, err := readReply(rd)
+			var redisErr RedisError
+			if err != nil && !errors.As(err, &redisErr) {
+				return nil, err
+			}
+			if err != nil {
+				v = redisErr
+			}
+			vals[i] = v
+		}
+		return vals, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
@@ -56,9 +56,10 @@ const (
 	CodeSuccess = 0
 
 	// 1xxx Client errors
-	CodeParamError = 1001
-	CodeParamType  = 1002
-	CodeJSONError  = 1003
+	CodeParamError      = 1001
+	CodeParamType       = 1002
+	CodeJSONError       = 1003
+	CodeTooManyRequests = 1004
 
 	// 2xxx Resource errors
 	CodeNotFound = 2001
@@ -73,9 +74,10 @@ const (
 	CodeTokenExpired = 4003
 
 	// 5xxx System errors
-	CodeInternal = 5001
-	CodeDatabase = 5002
-	CodeTimeout  = 5005
+	CodeInternal    = 5001
+	CodeDatabase    = 5002
+	CodeUnavailable = 5003
+	CodeTimeout     = 5005
 )
 
 // ========================
@@ -182,6 +184,14 @@ func Forbidden(c *gin.Context, message string) {
 	})
 }
 
+// TooManyRequests returns a rate limit exceeded response
+func TooManyRequests(c *gin.Context, message string) {
+	c.JSON(http.StatusTooManyRequests, Response{
+		Code:    CodeTooManyRequests,
+		Message: message,
+	})
+}
+
 // ServerError returns an internal server error response
 func ServerError(c *gin.Context, message string) {
 	c.JSON(http.StatusInternalServerError, Response{
@@ -198,11 +208,22 @@ func DatabaseError(c *gin.Context) {
 	})
 }
 
+// Unavailable returns a service unavailable response with details
+func Unavailable(c *gin.Context, message string, data interface{}) {
+	c.JSON(http.StatusServiceUnavailable, Response{
+		Code:    CodeUnavailable,
+		Message: message,
+		Data:    data,
+	})
+}
+
 // getHTTPStatus maps error codes to HTTP status codes
 func getHTTPStatus(code int) int {
 	switch {
 	case code == CodeSuccess:
 		return http.StatusOK
+	case code == CodeTooManyRequests:
+		return http.StatusTooManyRequests
 	case code >= 1000 && code < 2000:
 		return http.StatusBadRequest
 	case code == CodeNotFound:
@@ -217,6 +238,8 @@ func getHTTPStatus(code int) int {
 		return http.StatusUnauthorized
 	case code == CodeForbidden:
 		return http.StatusForbidden
+	case code == CodeUnavailable:
+		return http.StatusServiceUnavailable
 	default:
 		return http.StatusInternalServerError
 	}
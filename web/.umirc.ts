@@ -19,6 +19,11 @@ export default defineConfig({
       target: 'http://localhost:8080',
       changeOrigin: true,
     },
+    // gRPC-Web / Connect (grpc.web.enabled)
+    '/api.ExampleService': {
+      target: 'http://localhost:8080',
+      changeOrigin: true,
+    },
   },
   routes: [
     {
@@ -0,0 +1,56 @@
+import { TOKEN_KEY } from '../constants';
+
+// Calls api.ExampleService directly over the Connect protocol (JSON).
+// Requires grpc.web.enabled (and grpc.web.connect) on the server.
+const SERVICE = '/api.ExampleService';
+
+export interface ExampleMessage {
+  id: string; // uint64 is encoded as a string in proto JSON
+  name: string;
+  description?: string;
+  status?: string;
+  createdAt?: string;
+  updatedAt?: string;
+}
+
+async function call<T>(method: string, body: object): Promise<T> {
+  const token = localStorage.getItem(TOKEN_KEY);
+  const res = await fetch(`${SERVICE}/${method}`, {
+    method: 'POST',
+    headers: {
+      'Content-Type': 'application/json',
+      'Connect-Protocol-Version': '1',
+      ...(token ? { Authorization: `Bearer ${token}` } : {}),
+    },
+    body: JSON.stringify(body),
+  });
+  const data = await res.json();
+  if (!res.ok) {
+    throw new Error(data.message || data.code || res.statusText);
+  }
+  return data as T;
+}
+
+/** Get example by ID */
+export async function getExampleRpc(id: number) {
+  return call<ExampleMessage>('GetExample', { id: String(id) });
+}
+
+/** List examples with pagination */
+export async function listExamplesRpc(params: {
+  page?: number;
+  pageSize?: number;
+  keyword?: string;
+  status?: string;
+}) {
+  return call<{ items?: ExampleMessage[]; total?: string }>('ListExamples', params);
+}
+
+/** Create example */
+export async function createExampleRpc(data: {
+  name: string;
+  description?: string;
+  status?: string;
+}) {
+  return call<ExampleMessage>('CreateExample', data);
+}